@@ -6,6 +6,7 @@ import (
 
 	"cms/db"
 	"cms/internal/export"
+	"cms/internal/settings"
 
 	"github.com/spf13/cobra"
 )
@@ -14,6 +15,9 @@ var (
 	exportDir string
 	uploadDir string
 	siteTitle string
+	baseURL   string
+	workers   int
+	pageSize  int
 )
 
 var exportCmd = &cobra.Command{
@@ -28,6 +32,10 @@ func init() {
 	exportCmd.Flags().StringVarP(&exportDir, "output", "o", "./dist", "出力ディレクトリ")
 	exportCmd.Flags().StringVarP(&uploadDir, "uploads", "u", "./uploads", "画像ディレクトリ")
 	exportCmd.Flags().StringVarP(&siteTitle, "title", "t", "My Blog", "サイトタイトル")
+	exportCmd.Flags().StringVar(&baseURL, "base-url", "", "サイトの絶対URL（フィード生成に使用、未指定時は設定値）")
+	exportCmd.Flags().IntVar(&pageSize, "page-size", 0, "一覧1ページあたりの記事数（0なら分割しない、未指定時は設定値）")
+	exportCmd.Flags().StringVar(&actorEmail, "user", "", "実行するユーザーのメールアドレス（編集者以上、省略時は環境変数 CMS_USER）")
+	exportCmd.Flags().IntVarP(&workers, "workers", "j", 0, "並列描画数（0ならCPU数）")
 }
 
 func runExport(cmd *cobra.Command, args []string) {
@@ -37,15 +45,35 @@ func runExport(cmd *cobra.Command, args []string) {
 	}
 	defer db.Close()
 
+	actor, err := loadActor()
+	if err != nil {
+		log.Fatal("Failed to load user: ", err)
+	}
+
+	s, err := settings.NewService().Get()
+	if err != nil {
+		log.Fatal("Failed to load settings:", err)
+	}
+
+	// フラグで指定した値以外は設定ファイルの値を使う
+	cfg := export.NewConfig(s)
+	cfg.ExportDir = exportDir
+	cfg.UploadDir = uploadDir
+	cfg.SiteTitle = siteTitle
+	if baseURL != "" {
+		cfg.BaseURL = baseURL
+	}
+	if cmd.Flags().Changed("page-size") {
+		cfg.PageSize = pageSize
+	}
+	cfg.Workers = workers
+
 	svc := export.NewService(db.DB)
-	err := svc.Export(export.Config{
-		ExportDir: exportDir,
-		UploadDir: uploadDir,
-		SiteTitle: siteTitle,
-	})
+	result, err := svc.Export(actor, cfg)
 	if err != nil {
 		log.Fatal("Export failed:", err)
 	}
 
-	fmt.Printf("✓ エクスポート完了: %s\n", exportDir)
+	fmt.Printf("✓ エクスポート完了: %s (書き込み: %d, スキップ: %d, 削除: %d)\n",
+		exportDir, result.Written, result.Skipped, result.Deleted)
 }
@@ -21,7 +21,9 @@ title: "記事タイトル"
 slug: "article-slug"
 category: "カテゴリ名"
 tags: ["tag1", "tag2"]
-status: "draft"  # draft または published
+status: "draft"  # draft, in_review, approved, published または scheduled
+published_at: 2015-03-04T10:00:00+09:00  # 省略可（元の公開日時）
+updated_at: 2016-01-02                   # 省略可（元の更新日時）
 ---
 
 本文（Markdown）`,
@@ -31,6 +33,7 @@ status: "draft"  # draft または published
 
 func init() {
 	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&actorEmail, "user", "", "実行するユーザーのメールアドレス（作成者になる、省略時は環境変数 CMS_USER）")
 }
 
 func runImport(cmd *cobra.Command, args []string) {
@@ -40,10 +43,15 @@ func runImport(cmd *cobra.Command, args []string) {
 	}
 	defer db.Close()
 
+	actor, err := loadActor()
+	if err != nil {
+		log.Fatal("Failed to load user: ", err)
+	}
+
 	svc := importer.NewService(db.DB)
 
 	for _, filePath := range args {
-		article, err := svc.ImportMarkdown(filePath)
+		article, err := svc.ImportMarkdown(actor, filePath)
 		if err != nil {
 			log.Printf("✗ %s: %v\n", filePath, err)
 			continue
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"cms/db"
+	"cms/internal/publisher"
+
+	"github.com/spf13/cobra"
+)
+
+var publishExport bool
+
+var publishDueCmd = &cobra.Command{
+	Use:   "publish-due",
+	Short: "公開日時を過ぎた予約投稿を公開",
+	Long: `status が scheduled で published_at を過ぎた記事を公開します。
+サーバーを起動せずに cron などから実行できます。
+
+例（毎分実行）:
+* * * * * cd /path/to/cms && ./cms publish-due --export`,
+	Run: runPublishDue,
+}
+
+func init() {
+	rootCmd.AddCommand(publishDueCmd)
+	publishDueCmd.Flags().BoolVar(&publishExport, "export", false, "公開した記事があれば設定の出力先にエクスポート")
+}
+
+func runPublishDue(cmd *cobra.Command, args []string) {
+	// DB初期化
+	if err := db.Init(); err != nil {
+		log.Fatal("Failed to connect database:", err)
+	}
+	defer db.Close()
+
+	ids, err := publisher.New(db.DB, publishExport).PublishDue()
+	if err != nil {
+		log.Fatal("Publish failed:", err)
+	}
+
+	fmt.Printf("✓ 予約投稿を公開: %d 件 %v\n", len(ids), ids)
+}
@@ -1,23 +1,35 @@
 package cmd
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"cms/db"
 	"cms/internal/article"
+	"cms/internal/auth"
 	"cms/internal/category"
 	"cms/internal/export"
 	"cms/internal/image"
+	"cms/internal/preview"
+	"cms/internal/publisher"
 	"cms/internal/settings"
 	"cms/internal/tag"
 	"cms/internal/template"
+	"cms/internal/trash"
+	"cms/internal/user"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
 )
 
-var servePort string
+var (
+	servePort            string
+	servePreviewDrafts   bool
+	servePublishInterval time.Duration
+	serveExportOnPublish bool
+)
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
@@ -29,6 +41,9 @@ var serveCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(serveCmd)
 	serveCmd.Flags().StringVarP(&servePort, "port", "p", "8080", "サーバーポート")
+	serveCmd.Flags().BoolVar(&servePreviewDrafts, "preview-drafts", false, "プレビューサーバーに下書きも表示")
+	serveCmd.Flags().DurationVar(&servePublishInterval, "publish-interval", time.Minute, "予約投稿を公開する間隔（0なら公開しない）")
+	serveCmd.Flags().BoolVar(&serveExportOnPublish, "export-on-publish", false, "予約投稿を公開したらエクスポートする")
 }
 
 func runServe(cmd *cobra.Command, args []string) {
@@ -49,13 +64,19 @@ func runServe(cmd *cobra.Command, args []string) {
 		log.Fatal("Failed to initialize templates:", err)
 	}
 
+	// 予約投稿の公開
+	if servePublishInterval > 0 {
+		go publisher.New(db.DB, serveExportOnPublish).Run(context.Background(), servePublishInterval)
+	}
+
 	r := gin.Default()
 
 	// CORS設定
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Content-Type"},
+		AllowHeaders:     []string{"Content-Type", "If-Match", "Authorization"},
+		ExposeHeaders:    []string{"X-Total-Count", "ETag"},
 		AllowCredentials: true,
 	}))
 
@@ -63,9 +84,21 @@ func runServe(cmd *cobra.Command, args []string) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// 変更する API はログインが必要（セッションの Cookie か API トークン）
+	authHandler := auth.NewHandler(db.DB)
+
+	// プレビューサーバー（/preview/）。--preview-drafts では下書きも表示するためログインが必要
+	previewHandler := preview.NewHandler(db.DB, servePreviewDrafts)
+	previewHandler.RegisterRoutes(r.Group("", authHandler.Authenticate()))
+
 	// API routes
 	api := r.Group("/api")
+	api.Use(authHandler.Authenticate())
+	// 記事・テンプレート・設定などの変更をプレビューに通知
+	api.Use(previewHandler.NotifyOnChange())
 	{
+		authHandler.RegisterRoutes(api)
+
 		categoryHandler := category.NewHandler(db.DB)
 		categoryHandler.RegisterRoutes(api)
 
@@ -86,6 +119,12 @@ func runServe(cmd *cobra.Command, args []string) {
 
 		templateHandler := template.NewHandler(db.DB)
 		templateHandler.RegisterRoutes(api)
+
+		trashHandler := trash.NewHandler(db.DB)
+		trashHandler.RegisterRoutes(api)
+
+		userHandler := user.NewHandler(db.DB)
+		userHandler.RegisterRoutes(api)
 	}
 
 	r.Run(":" + servePort)
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"cms/db"
+	"cms/internal/user"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	userEmail    string
+	userName     string
+	userPassword string
+	userRole     string
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "ユーザーを管理",
+}
+
+var userAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "ユーザーを追加",
+	Long: `ユーザーを追加します。
+--password を省略すると標準入力からパスワードを読み込みます。
+--role は admin / editor / author のいずれかです（省略時は author）。
+
+例:
+cms user add --email kazu@example.com --name Kazu --role admin`,
+	Run: runUserAdd,
+}
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "ユーザー一覧",
+	Run:   runUserList,
+}
+
+var userPasswdCmd = &cobra.Command{
+	Use:   "passwd <email>",
+	Short: "ユーザーのパスワードを変更",
+	Long: `ユーザーのパスワードを変更します。
+--password を省略すると標準入力からパスワードを読み込みます。`,
+	Args: cobra.ExactArgs(1),
+	Run:  runUserPasswd,
+}
+
+func init() {
+	rootCmd.AddCommand(userCmd)
+	userCmd.AddCommand(userAddCmd, userListCmd, userPasswdCmd)
+
+	userAddCmd.Flags().StringVar(&userEmail, "email", "", "メールアドレス")
+	userAddCmd.Flags().StringVar(&userName, "name", "", "名前")
+	userAddCmd.Flags().StringVar(&userPassword, "password", "", "パスワード（省略時は標準入力から読み込む）")
+	userAddCmd.Flags().StringVar(&userRole, "role", user.RoleAuthor, "権限（admin / editor / author）")
+	userAddCmd.MarkFlagRequired("email")
+	userAddCmd.MarkFlagRequired("name")
+
+	userPasswdCmd.Flags().StringVar(&userPassword, "password", "", "新しいパスワード（省略時は標準入力から読み込む）")
+}
+
+func runUserAdd(cmd *cobra.Command, args []string) {
+	// DB初期化
+	if err := db.Init(); err != nil {
+		log.Fatal("Failed to connect database:", err)
+	}
+	defer db.Close()
+
+	password, err := readPassword(userPassword)
+	if err != nil {
+		log.Fatal("Failed to read password:", err)
+	}
+
+	// CLI はサーバーにアクセスできる管理者が使うため、システムとして実行する
+	u, err := user.NewService(db.DB).Create(user.System, userEmail, userName, password, userRole)
+	if err != nil {
+		log.Fatal("Failed to add user: ", err)
+	}
+	fmt.Printf("✓ ユーザーを追加: %s (ID: %d, %s)\n", u.Email, u.ID, u.Role)
+}
+
+func runUserList(cmd *cobra.Command, args []string) {
+	// DB初期化
+	if err := db.Init(); err != nil {
+		log.Fatal("Failed to connect database:", err)
+	}
+	defer db.Close()
+
+	users, err := user.NewService(db.DB).GetAll(user.System)
+	if err != nil {
+		log.Fatal("Failed to list users:", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEMAIL\tNAME\tROLE\tCREATED")
+	for _, u := range users {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", u.ID, u.Email, u.Name, u.Role, u.CreatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	w.Flush()
+}
+
+func runUserPasswd(cmd *cobra.Command, args []string) {
+	// DB初期化
+	if err := db.Init(); err != nil {
+		log.Fatal("Failed to connect database:", err)
+	}
+	defer db.Close()
+
+	password, err := readPassword(userPassword)
+	if err != nil {
+		log.Fatal("Failed to read password:", err)
+	}
+
+	u, err := user.NewService(db.DB).SetPasswordByEmail(args[0], password)
+	if err != nil {
+		log.Fatal("Failed to change password: ", err)
+	}
+	fmt.Printf("✓ パスワードを変更: %s\n", u.Email)
+}
+
+// actorEmail は import・export などを実行するユーザー（--user、省略時は環境変数 CMS_USER）
+var actorEmail string
+
+// loadActor は --user のメールアドレスのユーザーを返す。権限の確認はサービスで行う
+func loadActor() (*user.User, error) {
+	email := actorEmail
+	if email == "" {
+		email = os.Getenv("CMS_USER")
+	}
+	if email == "" {
+		return nil, errors.New("--user or CMS_USER is required")
+	}
+	u, err := user.NewRepository(db.DB).GetByEmail(strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		return nil, fmt.Errorf("user %s: %w", email, err)
+	}
+	return u, nil
+}
+
+// readPassword はフラグで指定がなければ標準入力の1行目をパスワードとして読む
+func readPassword(flag string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", errors.New("no password given")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
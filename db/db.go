@@ -56,13 +56,23 @@ func Init() error {
 
 func Migrate() error {
 	var m *migrate.Migrate
+	var fts bool
 
 	switch DBDriver {
 	case "sqlite3":
+		var err error
+		fts, err = ftsEnabled()
+		if err != nil {
+			return err
+		}
 		sourceDriver, err := iofs.New(sqliteMigrationsFS, "migrations/sqlite3")
 		if err != nil {
 			return err
 		}
+		if !fts {
+			// FTS5 なしでは全文検索のインデックスを作らない（検索は LIKE で行う）
+			sourceDriver = withoutFTS{sourceDriver}
+		}
 		dbDriver, err := sqlite3.WithInstance(DB, &sqlite3.Config{})
 		if err != nil {
 			return err
@@ -92,6 +102,12 @@ func Migrate() error {
 		return err
 	}
 
+	if DBDriver == "sqlite3" {
+		if err := syncFTS(fts); err != nil {
+			return err
+		}
+	}
+
 	log.Println("Database migrated")
 	return nil
 }
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"io"
+	"log"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+// ftsMigration は記事の全文検索（FTS5）のインデックスを作成するマイグレーション
+const (
+	ftsVersion   = 7
+	ftsMigration = "migrations/sqlite3/000007_create_articles_fts"
+)
+
+// ftsTriggers は articles_fts を articles と同期するトリガー
+var ftsTriggers = []string{"articles_fts_ai", "articles_fts_ad", "articles_fts_au"}
+
+// ftsEnabled は SQLite に FTS5 が組み込まれているか（sqlite_fts5 ビルドタグを付けてビルドした場合のみ）
+func ftsEnabled() (bool, error) {
+	var enabled bool
+	err := DB.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled)
+	return enabled, err
+}
+
+// withoutFTS は全文検索のマイグレーションを空にする（FTS5 なしでビルドした場合でもマイグレーションできるように）
+type withoutFTS struct {
+	source.Driver
+}
+
+func (d withoutFTS) ReadUp(version uint) (io.ReadCloser, string, error) {
+	if version == ftsVersion {
+		return io.NopCloser(strings.NewReader("")), "", nil
+	}
+	return d.Driver.ReadUp(version)
+}
+
+func (d withoutFTS) ReadDown(version uint) (io.ReadCloser, string, error) {
+	if version == ftsVersion {
+		return io.NopCloser(strings.NewReader("")), "", nil
+	}
+	return d.Driver.ReadDown(version)
+}
+
+// syncFTS はマイグレーション後に全文検索のインデックスをビルドに合わせる。
+// FTS5 が使えない場合は同期用のトリガーを削除し（記事の更新が失敗しないように）、検索は LIKE で行う。
+// FTS5 なしでマイグレーションした DB を FTS5 ありのビルドで開いた場合は、全文検索のマイグレーションを実行し直す
+func syncFTS(enabled bool) error {
+	if !enabled {
+		log.Println("Warning: SQLite FTS5 is not enabled, article search falls back to LIKE (build with -tags sqlite_fts5)")
+		for _, name := range ftsTriggers {
+			if _, err := DB.Exec("DROP TRIGGER IF EXISTS " + name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// トリガーがそろっていればインデックスは同期済み
+	var count int
+	err := DB.QueryRow(
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN (?, ?, ?)",
+		ftsTriggers[0], ftsTriggers[1], ftsTriggers[2],
+	).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count == len(ftsTriggers) {
+		return nil
+	}
+
+	log.Println("Rebuilding the article full-text search index")
+	for _, file := range []string{ftsMigration + ".down.sql", ftsMigration + ".up.sql"} {
+		query, err := sqliteMigrationsFS.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if _, err := DB.Exec(string(query)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+-- 記事の全文検索（日本語も部分一致で検索できるよう trigram トークナイザを使う）
+CREATE VIRTUAL TABLE articles_fts USING fts5(
+    title,
+    content,
+    content = 'articles',
+    content_rowid = 'id',
+    tokenize = 'trigram'
+);
+
+CREATE TRIGGER articles_fts_ai AFTER INSERT ON articles BEGIN
+    INSERT INTO articles_fts (rowid, title, content) VALUES (new.id, new.title, new.content);
+END;
+
+CREATE TRIGGER articles_fts_ad AFTER DELETE ON articles BEGIN
+    INSERT INTO articles_fts (articles_fts, rowid, title, content) VALUES ('delete', old.id, old.title, old.content);
+END;
+
+CREATE TRIGGER articles_fts_au AFTER UPDATE OF title, content ON articles BEGIN
+    INSERT INTO articles_fts (articles_fts, rowid, title, content) VALUES ('delete', old.id, old.title, old.content);
+    INSERT INTO articles_fts (rowid, title, content) VALUES (new.id, new.title, new.content);
+END;
+
+-- 既存の記事を登録
+INSERT INTO articles_fts (articles_fts) VALUES ('rebuild');
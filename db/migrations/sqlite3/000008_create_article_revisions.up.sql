@@ -0,0 +1,24 @@
+CREATE TABLE article_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    article_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    content TEXT NOT NULL,
+    status TEXT NOT NULL,
+    category_id INTEGER,
+    tag_ids TEXT NOT NULL DEFAULT '[]',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (article_id, revision),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
+-- 既存の記事は現在の内容をリビジョン1とする
+INSERT INTO article_revisions (article_id, revision, title, slug, content, status, category_id, tag_ids, created_at)
+SELECT
+    a.id, 1, a.title, a.slug, a.content, COALESCE(a.status, 'draft'), a.category_id,
+    (SELECT json_group_array(tag_id) FROM (
+        SELECT tag_id FROM article_tags WHERE article_id = a.id ORDER BY tag_id
+    )),
+    a.updated_at
+FROM articles a;
@@ -0,0 +1,3 @@
+ALTER TABLE tags DROP COLUMN deleted_at;
+ALTER TABLE categories DROP COLUMN deleted_at;
+ALTER TABLE articles DROP COLUMN deleted_at;
@@ -0,0 +1,4 @@
+-- ゴミ箱（論理削除）。deleted_at が入っている行はゴミ箱にある
+ALTER TABLE articles ADD COLUMN deleted_at DATETIME;
+ALTER TABLE categories ADD COLUMN deleted_at DATETIME;
+ALTER TABLE tags ADD COLUMN deleted_at DATETIME;
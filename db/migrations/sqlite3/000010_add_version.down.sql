@@ -0,0 +1,4 @@
+ALTER TABLE templates DROP COLUMN version;
+ALTER TABLE tags DROP COLUMN version;
+ALTER TABLE categories DROP COLUMN version;
+ALTER TABLE articles DROP COLUMN version;
@@ -0,0 +1,5 @@
+-- 楽観的排他制御のバージョン。更新のたびに1つ増やし、ETag として返す
+ALTER TABLE articles ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE tags ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE templates ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+-- ユーザーの権限（admin / editor / author）
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'author';
+
+-- 既存のユーザーは今までどおりすべての操作ができるよう管理者にする
+UPDATE users SET role = 'admin';
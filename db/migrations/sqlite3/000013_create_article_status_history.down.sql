@@ -0,0 +1 @@
+DROP TABLE article_status_history;
@@ -0,0 +1,14 @@
+-- 記事のステータスの変更履歴（レビューの差し戻しのコメントもここに残す）
+CREATE TABLE article_status_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    article_id INTEGER NOT NULL,
+    from_status TEXT,                  -- 作成時は NULL
+    to_status TEXT NOT NULL,
+    user_id INTEGER,                   -- 予約投稿の公開など、ユーザーの操作でなければ NULL
+    comment TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_article_status_history_article_id ON article_status_history(article_id);
@@ -2,8 +2,15 @@ package article
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+
+	"cms/internal/auth"
+	"cms/internal/etag"
+	"cms/internal/user"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,11 +25,20 @@ func NewHandler(db *sql.DB) *Handler {
 
 func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	r.GET("/articles", h.GetAll)
+	r.GET("/articles/search", h.Search)
+	r.GET("/articles/review", h.ReviewQueue)
 	r.GET("/articles/:id", h.GetByID)
 	r.POST("/articles", h.Create)
 	r.PUT("/articles/:id", h.Update)
 	r.POST("/articles/:id/toggle-status", h.ToggleStatus)
+	r.POST("/articles/:id/status", h.Transition)
+	r.GET("/articles/:id/status-history", h.GetStatusHistory)
 	r.DELETE("/articles/:id", h.Delete)
+
+	r.GET("/articles/:id/revisions", h.GetRevisions)
+	r.GET("/articles/:id/revisions/diff", h.DiffRevisions)
+	r.GET("/articles/:id/revisions/:rev", h.GetRevision)
+	r.POST("/articles/:id/revisions/:rev/restore", h.RestoreRevision)
 }
 
 type CreateRequest struct {
@@ -30,9 +46,10 @@ type CreateRequest struct {
 	Slug       string  `json:"slug" binding:"required"`
 	Content    string  `json:"content"`
 	Status     string  `json:"status"`
-	AuthorID   int64   `json:"author_id" binding:"required"`
 	CategoryID *int64  `json:"category_id"`
 	TagIDs     []int64 `json:"tag_ids"`
+	// 公開日時（予約投稿では必須、公開では省略すると現在時刻）
+	PublishedAt *time.Time `json:"published_at"`
 }
 
 type UpdateRequest struct {
@@ -42,25 +59,286 @@ type UpdateRequest struct {
 	Status     string  `json:"status"`
 	CategoryID *int64  `json:"category_id"`
 	TagIDs     []int64 `json:"tag_ids"`
+	// 公開日時（省略すると元の公開日時を保つ）
+	PublishedAt *time.Time `json:"published_at"`
+	// true なら公開日時を付け直す（公開なら現在時刻、下書きなら未設定）
+	ResetPublishedAt bool `json:"reset_published_at"`
+}
+
+type TransitionRequest struct {
+	Status string `json:"status" binding:"required"`
+	// 差し戻し（in_review・approved から draft）では必須
+	Comment string `json:"comment"`
+	// 公開日時（予約投稿では必須、公開では省略すると元の公開日時か現在時刻）
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+// 記事一覧で並び替えに使える項目
+var listSortFields = map[string]bool{
+	"id":           true,
+	"title":        true,
+	"created_at":   true,
+	"updated_at":   true,
+	"published_at": true,
 }
 
+// 記事一覧の件数（limit）の上限
+const maxListLimit = 100
+
+// GetAll は記事一覧を返す。絞り込み・並び替え・ページングはクエリパラメータで指定し、
+// ページングする前の件数は X-Total-Count ヘッダーで返す
 func (h *Handler) GetAll(c *gin.Context) {
-	articles, err := h.service.GetAll()
+	params, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	params.Include, err = parseInclude(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	articles, total, err := h.service.List(params)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	respondList(c, articles, total, params)
+}
+
+// ReviewQueue はレビュー待ち（in_review）の記事一覧を返す。
+// 一覧と同じクエリパラメータを使え（status は無視）、sort を省略すると更新が古い順
+func (h *Handler) ReviewQueue(c *gin.Context) {
+	params, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("sort") == "" {
+		params.Sort = "updated_at"
+		params.Order = c.DefaultQuery("order", "asc")
+	}
+	params.Include, err = parseInclude(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	articles, total, err := h.service.ReviewQueue(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondList(c, articles, total, params)
+}
+
+// respondList は記事一覧を返す（ページングする前の件数は X-Total-Count ヘッダー）
+func respondList(c *gin.Context, articles []Article, total int, params ListParams) {
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	if params.WithoutContent {
+		summaries := make([]ArticleSummary, len(articles))
+		for i, a := range articles {
+			summaries[i] = ArticleSummary{Article: a}
+		}
+		c.JSON(http.StatusOK, summaries)
+		return
+	}
 	c.JSON(http.StatusOK, articles)
 }
 
+// parseInclude は include= に指定された関連を読む（author, category, tags をカンマ区切り）。
+// 指定がなければ DefaultInclude、空なら関連を含めない
+func parseInclude(c *gin.Context) (Include, error) {
+	v, ok := c.GetQuery("include")
+	if !ok {
+		return DefaultInclude, nil
+	}
+
+	var inc Include
+	for _, name := range strings.Split(v, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "":
+		case "author":
+			inc.Author = true
+		case "category":
+			inc.Category = true
+		case "tags":
+			inc.Tags = true
+		default:
+			return inc, errors.New("invalid include: " + name)
+		}
+	}
+	return inc, nil
+}
+
+func parseListParams(c *gin.Context) (ListParams, error) {
+	p := ListParams{
+		Status: c.Query("status"),
+		Sort:   c.DefaultQuery("sort", "id"),
+		Order:  c.DefaultQuery("order", "desc"),
+	}
+
+	if p.Status != "" && !ValidStatus(p.Status) {
+		return p, errors.New("invalid status")
+	}
+	if !listSortFields[p.Sort] {
+		return p, errors.New("invalid sort")
+	}
+	if p.Order != "asc" && p.Order != "desc" {
+		return p, errors.New("invalid order")
+	}
+
+	var err error
+	if p.CategoryID, err = queryID(c, "category_id"); err != nil {
+		return p, err
+	}
+	if p.TagID, err = queryID(c, "tag_id"); err != nil {
+		return p, err
+	}
+	if p.AuthorID, err = queryID(c, "author_id"); err != nil {
+		return p, err
+	}
+
+	if p.CreatedFrom, err = queryTime(c, "created_from", false); err != nil {
+		return p, err
+	}
+	if p.CreatedTo, err = queryTime(c, "created_to", true); err != nil {
+		return p, err
+	}
+	if p.PublishedFrom, err = queryTime(c, "published_from", false); err != nil {
+		return p, err
+	}
+	if p.PublishedTo, err = queryTime(c, "published_to", true); err != nil {
+		return p, err
+	}
+
+	if v := c.Query("limit"); v != "" {
+		p.Limit, err = strconv.Atoi(v)
+		if err != nil || p.Limit < 1 || p.Limit > maxListLimit {
+			return p, errors.New("invalid limit")
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		p.Offset, err = strconv.Atoi(v)
+		if err != nil || p.Offset < 0 {
+			return p, errors.New("invalid offset")
+		}
+	}
+
+	if v := c.Query("exclude_content"); v != "" {
+		p.WithoutContent, err = strconv.ParseBool(v)
+		if err != nil {
+			return p, errors.New("invalid exclude_content")
+		}
+	}
+	return p, nil
+}
+
+// queryID は ID のクエリパラメータを読む（未指定なら nil）
+func queryID(c *gin.Context, name string) (*int64, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid " + name)
+	}
+	return &id, nil
+}
+
+// queryTime は日時のクエリパラメータを読む（RFC3339 または YYYY-MM-DD）。
+// 期間の終わり（end）に日付だけを指定した場合はその日を含むよう翌日の 0 時にする
+func queryTime(c *gin.Context, name string, end bool) (*time.Time, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, v, time.Local)
+	if err != nil {
+		return nil, errors.New("invalid " + name)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// 検索結果の件数（limit）の既定値と上限
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+func (h *Handler) Search(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	params := SearchParams{Query: q, Status: c.Query("status"), Limit: defaultSearchLimit}
+	if params.Status != "" && !ValidStatus(params.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+	if v := c.Query("category_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+			return
+		}
+		params.CategoryID = &id
+	}
+	if v := c.Query("tag_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag_id"})
+			return
+		}
+		params.TagID = &id
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		params.Limit = limit
+	}
+	include, err := parseInclude(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	params.Include = include
+
+	results, err := h.service.Search(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
 func (h *Handler) GetByID(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
+	include, err := parseInclude(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	article, err := h.service.GetByID(id)
+	article, err := h.service.Get(id, include)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
@@ -69,74 +347,347 @@ func (h *Handler) GetByID(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	etag.Set(c, etag.Version(article.Version))
 	c.JSON(http.StatusOK, article)
 }
 
+// Create は記事を作成する（作成者はログイン中のユーザー）
 func (h *Handler) Create(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	var req CreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	article, err := h.service.Create(req.Title, req.Slug, req.Content, req.Status, req.AuthorID, req.CategoryID, req.TagIDs)
+	article, err := h.service.Create(u, req.Title, req.Slug, req.Content, req.Status, u.ID, req.CategoryID, req.TagIDs, req.PublishedAt)
 	if err != nil {
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrInvalidStatus) || errors.Is(err, ErrInvalidSchedule) || errors.Is(err, ErrInvalidAuthor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrSlugTaken) || errors.Is(err, ErrSlugInTrash) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	etag.Set(c, etag.Version(article.Version))
 	c.JSON(http.StatusCreated, article)
 }
 
 func (h *Handler) Update(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
+	// 他の人の更新を上書きしないよう、取得したときの ETag を If-Match で送ってもらう
+	version, ok := etag.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	article, err := h.service.Update(id, req.Title, req.Slug, req.Content, req.Status, req.CategoryID, req.TagIDs)
+	article, err := h.service.Update(u, id, req.Title, req.Slug, req.Content, req.Status, req.CategoryID, req.TagIDs, req.PublishedAt, req.ResetPublishedAt, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.updateFailed(c, id, err, "article not found")
 		return
 	}
+	etag.Set(c, etag.Version(article.Version))
 	c.JSON(http.StatusOK, article)
 }
 
+// updateFailed は記事の更新（リビジョンの復元を含む）のエラーをステータスコードに変換して返す
+func (h *Handler) updateFailed(c *gin.Context, id int64, err error, notFound string) {
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return
+	}
+	if errors.Is(err, etag.ErrMismatch) {
+		h.preconditionFailed(c, id)
+		return
+	}
+	if errors.Is(err, user.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, ErrInvalidTransition) || errors.Is(err, ErrSlugTaken) || errors.Is(err, ErrSlugInTrash) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, ErrInvalidStatus) || errors.Is(err, ErrInvalidSchedule) || errors.Is(err, ErrCommentRequired) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// preconditionFailed は 412 と現在の記事を返す
+func (h *Handler) preconditionFailed(c *gin.Context, id int64) {
+	current, err := h.service.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	etag.PreconditionFailed(c, etag.Version(current.Version), current)
+}
+
 func (h *Handler) ToggleStatus(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	// ?reset_published_at=true なら再公開で公開日時を現在時刻にする
+	reset := c.Query("reset_published_at") == "true"
+
+	article, err := h.service.ToggleStatus(u, id, reset)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+			return
+		}
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	etag.Set(c, etag.Version(article.Version))
+	c.JSON(http.StatusOK, article)
+}
+
+// Transition は記事のステータスを変更する（レビュー依頼・承認・差し戻し・公開など）
+func (h *Handler) Transition(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
-	article, err := h.service.ToggleStatus(id)
+	var req TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	article, err := h.service.Transition(u, id, req.Status, req.Comment, req.PublishedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
 			return
 		}
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrInvalidStatus) || errors.Is(err, ErrInvalidSchedule) || errors.Is(err, ErrCommentRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	etag.Set(c, etag.Version(article.Version))
 	c.JSON(http.StatusOK, article)
 }
 
+// GetStatusHistory は記事のステータスの変更履歴を新しい順に返す
+func (h *Handler) GetStatusHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	history, err := h.service.GetStatusHistory(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
 func (h *Handler) Delete(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(u, id); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+			return
+		}
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusNoContent, nil)
 }
+
+func (h *Handler) GetRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+func (h *Handler) GetRevision(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	rev, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision"})
+		return
+	}
+
+	revision, err := h.service.GetRevision(id, rev)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "revision not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, revision)
+}
+
+// DiffRevisions は ?from=1&to=3 のリビジョン間の unified diff を返す（to を省略すると最新のリビジョン）
+func (h *Handler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	from, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+		return
+	}
+	var to int64
+	if v := c.Query("to"); v != "" {
+		to, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+			return
+		}
+	}
+
+	diff, err := h.service.DiffRevisions(id, from, to)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "revision not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
+func (h *Handler) RestoreRevision(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	rev, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision"})
+		return
+	}
+
+	// 復元も記事の更新なので、更新と同じく If-Match が必要
+	version, ok := etag.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	article, err := h.service.RestoreRevision(u, id, rev, version)
+	if err != nil {
+		h.updateFailed(c, id, err, "revision not found")
+		return
+	}
+	etag.Set(c, etag.Version(article.Version))
+	c.JSON(http.StatusOK, article)
+}
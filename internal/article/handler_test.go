@@ -0,0 +1,90 @@
+package article
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testContext はクエリ文字列だけを持つリクエストの gin.Context を作る
+func testContext(query string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/articles?"+query, nil)
+	return c
+}
+
+func TestParseListParams(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantErr string
+		check   func(t *testing.T, p ListParams)
+	}{
+		{
+			query: "",
+			check: func(t *testing.T, p ListParams) {
+				if p.Sort != "id" || p.Order != "desc" || p.Limit != 0 || p.Offset != 0 {
+					t.Errorf("defaults = %q %q %d %d", p.Sort, p.Order, p.Limit, p.Offset)
+				}
+			},
+		},
+		{
+			query: "status=published&category_id=2&tag_id=3&author_id=4&sort=title&order=asc&limit=10&offset=20&exclude_content=true",
+			check: func(t *testing.T, p ListParams) {
+				if p.Status != StatusPublished || *p.CategoryID != 2 || *p.TagID != 3 || *p.AuthorID != 4 {
+					t.Errorf("filters = %q %d %d %d", p.Status, *p.CategoryID, *p.TagID, *p.AuthorID)
+				}
+				if p.Sort != "title" || p.Order != "asc" || p.Limit != 10 || p.Offset != 20 || !p.WithoutContent {
+					t.Errorf("paging = %q %q %d %d %v", p.Sort, p.Order, p.Limit, p.Offset, p.WithoutContent)
+				}
+			},
+		},
+		{
+			// 日付だけの終わりはその日を含むよう翌日の 0 時（未満）にする
+			query: "created_from=2024-01-01&created_to=2024-01-31",
+			check: func(t *testing.T, p ListParams) {
+				from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+				to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)
+				if !p.CreatedFrom.Equal(from) || !p.CreatedTo.Equal(to) {
+					t.Errorf("created = %v - %v, want %v - %v", p.CreatedFrom, p.CreatedTo, from, to)
+				}
+			},
+		},
+		{
+			query: "published_to=2024-01-31T12:00:00Z",
+			check: func(t *testing.T, p ListParams) {
+				want := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+				if !p.PublishedTo.Equal(want) {
+					t.Errorf("published_to = %v, want %v", p.PublishedTo, want)
+				}
+			},
+		},
+		{query: "status=archived", wantErr: "invalid status"},
+		{query: "sort=content", wantErr: "invalid sort"},
+		{query: "order=up", wantErr: "invalid order"},
+		{query: "category_id=abc", wantErr: "invalid category_id"},
+		{query: "tag_id=1.5", wantErr: "invalid tag_id"},
+		{query: "created_from=yesterday", wantErr: "invalid created_from"},
+		{query: "limit=0", wantErr: "invalid limit"},
+		{query: "limit=101", wantErr: "invalid limit"},
+		{query: "offset=-1", wantErr: "invalid offset"},
+		{query: "exclude_content=maybe", wantErr: "invalid exclude_content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			p, err := parseListParams(testContext(tt.query))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			tt.check(t, p)
+		})
+	}
+}
@@ -1,13 +1,57 @@
 package article
 
 import (
+	"errors"
 	"time"
 
 	"cms/internal/category"
 	"cms/internal/tag"
-	"cms/internal/user"
 )
 
+// 記事のステータス
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review" // レビュー待ち
+	StatusApproved  = "approved"  // レビュー済み（公開待ち）
+	StatusPublished = "published"
+	StatusScheduled = "scheduled" // published_at になったら公開される
+)
+
+var (
+	ErrInvalidStatus     = errors.New("status must be draft, in_review, approved, published or scheduled")
+	ErrInvalidSchedule   = errors.New("scheduled articles require a future published_at")
+	ErrInvalidAuthor     = errors.New("author_id does not refer to an existing user")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrCommentRequired   = errors.New("comment is required when rejecting an article")
+	ErrSlugTaken         = errors.New("slug is already in use")
+	ErrSlugInTrash       = errors.New("slug is used by an article in the trash (restore or purge it first)")
+)
+
+// ValidStatus はステータスとして使える値か
+func ValidStatus(status string) bool {
+	switch status {
+	case StatusDraft, StatusInReview, StatusApproved, StatusPublished, StatusScheduled:
+		return true
+	}
+	return false
+}
+
+// transitions はステータスごとに変更できる先のステータス。
+// draft → in_review → approved → published（または scheduled）の順に進み、
+// in_review・approved から draft に戻すのは差し戻し（コメントが必要）
+var transitions = map[string][]string{
+	StatusDraft:     {StatusInReview},
+	StatusInReview:  {StatusApproved, StatusDraft},
+	StatusApproved:  {StatusPublished, StatusScheduled, StatusDraft},
+	StatusScheduled: {StatusPublished, StatusDraft},
+	StatusPublished: {StatusDraft},
+}
+
+// isRejection は差し戻し（レビュー中・レビュー済みの記事を下書きに戻す）か
+func isRejection(from, to string) bool {
+	return to == StatusDraft && (from == StatusInReview || from == StatusApproved)
+}
+
 type Article struct {
 	ID          int64      `json:"id"`
 	Title       string     `json:"title"`
@@ -19,9 +63,127 @@ type Article struct {
 	PublishedAt *time.Time `json:"published_at"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+	// 更新のたびに増えるバージョン（ETag）
+	Version int64 `json:"version,omitempty"`
+	// ゴミ箱に入れた日時（ゴミ箱の一覧でのみ返す）
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 
 	// Relations (for response)
-	Author   *user.User         `json:"author,omitempty"`
+	Author   *Author            `json:"author,omitempty"`
 	Category *category.Category `json:"category,omitempty"`
 	Tags     []tag.Tag          `json:"tags,omitempty"`
 }
+
+// Author は記事と一緒に返す作成者（誰でも取得できるため、メールアドレスや権限は含めない）
+type Author struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Revision は記事の作成・更新ごとに保存するスナップショット
+type Revision struct {
+	ID         int64     `json:"id"`
+	ArticleID  int64     `json:"article_id"`
+	Revision   int64     `json:"revision"`
+	Title      string    `json:"title"`
+	Slug       string    `json:"slug"`
+	Content    string    `json:"content,omitempty"` // 一覧では省略
+	Status     string    `json:"status"`
+	CategoryID *int64    `json:"category_id"`
+	TagIDs     []int64   `json:"tag_ids"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// StatusChange は記事のステータスの変更履歴
+type StatusChange struct {
+	ID         int64   `json:"id"`
+	ArticleID  int64   `json:"article_id"`
+	FromStatus *string `json:"from_status"` // 作成時は null
+	ToStatus   string  `json:"to_status"`
+	// 変更したユーザー（予約投稿の公開などでは null）
+	UserID    *int64    `json:"user_id"`
+	UserName  *string   `json:"user_name"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RevisionDiff はリビジョン間の unified diff
+type RevisionDiff struct {
+	From int64  `json:"from"`
+	To   int64  `json:"to"`
+	Diff string `json:"diff"`
+}
+
+// Include は記事と一緒に読み込む関連（API では include= で指定）。
+// 含めない関連はクエリで JOIN しない
+type Include struct {
+	Author   bool
+	Category bool
+	Tags     bool
+}
+
+// DefaultInclude は include= を指定しなかったときの関連（タグだけ）
+var DefaultInclude = Include{Tags: true}
+
+// IncludeAll はすべての関連（エクスポートや更新時の確認など、API 以外で使う）
+var IncludeAll = Include{Author: true, Category: true, Tags: true}
+
+// ListParams は記事一覧の絞り込み・並び順・ページングの条件
+type ListParams struct {
+	Status        string
+	CategoryID    *int64
+	TagID         *int64
+	AuthorID      *int64
+	CreatedFrom   *time.Time // 以上
+	CreatedTo     *time.Time // 未満
+	PublishedFrom *time.Time // 以上
+	PublishedTo   *time.Time // 未満
+	Sort          string     // id, title, created_at, updated_at, published_at
+	Order         string     // asc, desc
+	Limit         int        // 0 なら全件
+	Offset        int
+	// WithoutContent が true なら本文を読み込まない
+	WithoutContent bool
+	// Include は読み込む関連
+	Include Include
+}
+
+// ArticleSummary は本文を除いた記事（一覧で本文を省略する場合のレスポンス）
+type ArticleSummary struct {
+	Article
+	Content *string `json:"content,omitempty"`
+}
+
+// SearchParams は全文検索の条件
+type SearchParams struct {
+	Query      string
+	Status     string
+	CategoryID *int64
+	TagID      *int64
+	Limit      int
+	Include    Include // 読み込む関連
+}
+
+// SearchResult は全文検索の結果（本文の代わりに一致箇所の抜粋を返す）。
+// TitleHighlight と Snippet は HTML エスケープ済みで、一致した部分を <mark> で囲む
+type SearchResult struct {
+	ID             int64      `json:"id"`
+	Title          string     `json:"title"`
+	Slug           string     `json:"slug"`
+	Status         string     `json:"status"`
+	AuthorID       int64      `json:"author_id"`
+	CategoryID     *int64     `json:"category_id"`
+	PublishedAt    *time.Time `json:"published_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Score          float64    `json:"score"`
+	TitleHighlight string     `json:"title_highlight"`
+	Snippet        string     `json:"snippet"`
+
+	// Relations (for response)
+	Author   *Author            `json:"author,omitempty"`
+	Category *category.Category `json:"category,omitempty"`
+	Tags     []tag.Tag          `json:"tags,omitempty"`
+
+	content string
+}
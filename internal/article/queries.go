@@ -14,17 +14,37 @@ func loadQuery(name string) string {
 }
 
 var (
-	queryGetAll            = loadQuery("get_all.sql")
-	queryGetByID           = loadQuery("get_by_id.sql")
-	queryGetPublished      = loadQuery("get_published.sql")
-	queryGetByCategory     = loadQuery("get_by_category.sql")
-	queryGetByTag          = loadQuery("get_by_tag.sql")
-	queryCreate            = loadQuery("create.sql")
-	queryUpdate            = loadQuery("update.sql")
-	queryToggleToPublished = loadQuery("toggle_to_published.sql")
-	queryToggleToDraft     = loadQuery("toggle_to_draft.sql")
-	queryPublish           = loadQuery("toggle_to_published.sql") // Publishも同じSQLを使用
-	queryDelete            = loadQuery("delete.sql")
-	queryDeleteTags        = loadQuery("delete_tags.sql")
-	queryInsertTag         = loadQuery("insert_tag.sql")
+	queryGetAll        = loadQuery("get_all.sql")
+	queryList          = loadQuery("list.sql")
+	queryCount         = loadQuery("count.sql")
+	queryGetByID       = loadQuery("get_by_id.sql")
+	queryGetPublished  = loadQuery("get_published.sql")
+	queryGetByCategory = loadQuery("get_by_category.sql")
+	queryGetByTag      = loadQuery("get_by_tag.sql")
+	querySearch        = loadQuery("search.sql")
+	querySearchLike    = loadQuery("search_like.sql")
+	queryFTSEnabled    = loadQuery("fts_enabled.sql")
+	queryCreate        = loadQuery("create.sql")
+	queryUpdate        = loadQuery("update.sql")
+	querySetStatus     = loadQuery("set_status.sql")
+	queryPublishDue    = loadQuery("publish_due.sql")
+	querySetTimestamps = loadQuery("set_timestamps.sql")
+	queryDelete        = loadQuery("delete.sql")
+	queryGetTrashed    = loadQuery("get_trashed.sql")
+	queryRestore       = loadQuery("restore.sql")
+	queryPurge         = loadQuery("purge.sql")
+	queryDeleteTags    = loadQuery("delete_tags.sql")
+	queryInsertTag     = loadQuery("insert_tag.sql")
+	querySlugInTrash   = loadQuery("slug_in_trash.sql")
+
+	queryCreateRevision    = loadQuery("create_revision.sql")
+	queryPruneRevisions    = loadQuery("prune_revisions.sql")
+	queryGetRevisions      = loadQuery("get_revisions.sql")
+	queryGetRevision       = loadQuery("get_revision.sql")
+	queryGetLatestRevision = loadQuery("get_latest_revision.sql")
+	queryDeleteRevisions   = loadQuery("delete_revisions.sql")
+
+	queryCreateStatusChange  = loadQuery("create_status_change.sql")
+	queryGetStatusHistory    = loadQuery("get_status_history.sql")
+	queryDeleteStatusHistory = loadQuery("delete_status_history.sql")
 )
@@ -0,0 +1,18 @@
+SELECT COUNT(*)
+FROM articles a
+WHERE a.deleted_at IS NULL
+    AND (:status = '' OR a.status = :status)
+    AND (:category_id IS NULL OR EXISTS (
+        SELECT 1 FROM categories c_filter
+        WHERE c_filter.id = a.category_id AND c_filter.id = :category_id AND c_filter.deleted_at IS NULL
+    ))
+    AND (:author_id IS NULL OR a.author_id = :author_id)
+    AND (:tag_id IS NULL OR EXISTS (
+        SELECT 1 FROM article_tags at_filter
+        INNER JOIN tags t_filter ON t_filter.id = at_filter.tag_id AND t_filter.deleted_at IS NULL
+        WHERE at_filter.article_id = a.id AND at_filter.tag_id = :tag_id
+    ))
+    AND (:created_from IS NULL OR julianday(a.created_at) >= julianday(:created_from))
+    AND (:created_to IS NULL OR julianday(a.created_at) < julianday(:created_to))
+    AND (:published_from IS NULL OR julianday(a.published_at) >= julianday(:published_from))
+    AND (:published_to IS NULL OR julianday(a.published_at) < julianday(:published_to))
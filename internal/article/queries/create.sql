@@ -1,3 +1,2 @@
-INSERT INTO articles (title, slug, content, status, author_id, category_id, created_at, updated_at) 
-VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-
+INSERT INTO articles (title, slug, content, status, author_id, category_id, published_at, created_at, updated_at) 
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
@@ -0,0 +1,4 @@
+INSERT INTO article_revisions (article_id, revision, title, slug, content, status, category_id, tag_ids, created_at)
+SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, ?, ?, ?, ?
+FROM article_revisions
+WHERE article_id = ?
@@ -0,0 +1,2 @@
+INSERT INTO article_status_history (article_id, from_status, to_status, user_id, comment, created_at)
+VALUES (?, ?, ?, ?, ?, ?)
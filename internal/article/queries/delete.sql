@@ -1,2 +1,3 @@
-DELETE FROM articles WHERE id = ?
-
+UPDATE articles 
+SET deleted_at = ? 
+WHERE id = ? AND deleted_at IS NULL
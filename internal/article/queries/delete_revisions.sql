@@ -0,0 +1 @@
+DELETE FROM article_revisions WHERE article_id = ?
@@ -0,0 +1 @@
+DELETE FROM article_status_history WHERE article_id = ?
@@ -0,0 +1 @@
+SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = 'articles_fts_ai'
@@ -1,10 +1,14 @@
 SELECT 
     a.id, a.title, a.slug, a.content, a.status, 
-    a.author_id, a.category_id, a.published_at, a.created_at, a.updated_at,
+    a.author_id, a.category_id, a.published_at, a.created_at, a.updated_at, a.version,
+    c.id AS cat_id, c.name AS category_name, c.slug AS category_slug, c.created_at AS category_created_at, c.version AS category_version,
+    u.id AS user_id, u.name AS author_name,
     t.id AS tag_id, t.name AS tag_name, t.slug AS tag_slug, t.created_at AS tag_created_at
 FROM articles a
+LEFT JOIN categories c ON a.category_id = c.id AND c.deleted_at IS NULL
+LEFT JOIN users u ON a.author_id = u.id
 LEFT JOIN article_tags at ON a.id = at.article_id
-LEFT JOIN tags t ON at.tag_id = t.id
-WHERE a.status = 'published' AND a.category_id = ?
-ORDER BY a.published_at DESC, t.id ASC
+LEFT JOIN tags t ON at.tag_id = t.id AND t.deleted_at IS NULL
+WHERE a.status = 'published' AND a.category_id = ? AND c.id IS NOT NULL AND a.deleted_at IS NULL
+ORDER BY julianday(a.published_at) DESC, a.id DESC, t.id ASC
 
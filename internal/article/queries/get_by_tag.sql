@@ -1,11 +1,16 @@
 SELECT 
     a.id, a.title, a.slug, a.content, a.status, 
-    a.author_id, a.category_id, a.published_at, a.created_at, a.updated_at,
+    a.author_id, a.category_id, a.published_at, a.created_at, a.updated_at, a.version,
+    c.id AS cat_id, c.name AS category_name, c.slug AS category_slug, c.created_at AS category_created_at, c.version AS category_version,
+    u.id AS user_id, u.name AS author_name,
     t2.id AS tag_id, t2.name AS tag_name, t2.slug AS tag_slug, t2.created_at AS tag_created_at
 FROM articles a
 INNER JOIN article_tags at_filter ON a.id = at_filter.article_id AND at_filter.tag_id = ?
+INNER JOIN tags t_filter ON t_filter.id = at_filter.tag_id AND t_filter.deleted_at IS NULL
+LEFT JOIN categories c ON a.category_id = c.id AND c.deleted_at IS NULL
+LEFT JOIN users u ON a.author_id = u.id
 LEFT JOIN article_tags at ON a.id = at.article_id
-LEFT JOIN tags t2 ON at.tag_id = t2.id
-WHERE a.status = 'published'
-ORDER BY a.published_at DESC, t2.id ASC
+LEFT JOIN tags t2 ON at.tag_id = t2.id AND t2.deleted_at IS NULL
+WHERE a.status = 'published' AND a.deleted_at IS NULL
+ORDER BY julianday(a.published_at) DESC, a.id DESC, t2.id ASC
 
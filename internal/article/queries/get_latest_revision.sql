@@ -0,0 +1,5 @@
+SELECT id, article_id, revision, title, slug, content, status, category_id, tag_ids, created_at
+FROM article_revisions
+WHERE article_id = ?
+ORDER BY revision DESC
+LIMIT 1
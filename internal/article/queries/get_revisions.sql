@@ -0,0 +1,4 @@
+SELECT id, article_id, revision, title, slug, '' AS content, status, category_id, tag_ids, created_at
+FROM article_revisions
+WHERE article_id = ?
+ORDER BY revision DESC
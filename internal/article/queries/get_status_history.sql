@@ -0,0 +1,5 @@
+SELECT h.id, h.article_id, h.from_status, h.to_status, h.user_id, u.name AS user_name, h.comment, h.created_at
+FROM article_status_history h
+LEFT JOIN users u ON h.user_id = u.id
+WHERE h.article_id = ?
+ORDER BY h.id DESC
@@ -0,0 +1,6 @@
+SELECT 
+    a.id, a.title, a.slug, a.status, 
+    a.author_id, a.category_id, a.published_at, a.created_at, a.updated_at, a.deleted_at
+FROM articles a
+WHERE a.deleted_at IS NOT NULL
+ORDER BY a.deleted_at DESC
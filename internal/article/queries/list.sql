@@ -0,0 +1,49 @@
+WITH filtered AS (
+    SELECT
+        a.id,
+        CASE :sort
+            WHEN 'title' THEN a.title
+            WHEN 'created_at' THEN julianday(a.created_at)
+            WHEN 'updated_at' THEN julianday(a.updated_at)
+            WHEN 'published_at' THEN julianday(a.published_at)
+            ELSE a.id
+        END AS sort_key
+    FROM articles a
+    WHERE a.deleted_at IS NULL
+        AND (:status = '' OR a.status = :status)
+        AND (:category_id IS NULL OR EXISTS (
+            SELECT 1 FROM categories c_filter
+            WHERE c_filter.id = a.category_id AND c_filter.id = :category_id AND c_filter.deleted_at IS NULL
+        ))
+        AND (:author_id IS NULL OR a.author_id = :author_id)
+        AND (:tag_id IS NULL OR EXISTS (
+            SELECT 1 FROM article_tags at_filter
+            INNER JOIN tags t_filter ON t_filter.id = at_filter.tag_id AND t_filter.deleted_at IS NULL
+            WHERE at_filter.article_id = a.id AND at_filter.tag_id = :tag_id
+        ))
+        AND (:created_from IS NULL OR julianday(a.created_at) >= julianday(:created_from))
+        AND (:created_to IS NULL OR julianday(a.created_at) < julianday(:created_to))
+        AND (:published_from IS NULL OR julianday(a.published_at) >= julianday(:published_from))
+        AND (:published_to IS NULL OR julianday(a.published_at) < julianday(:published_to))
+),
+page AS (
+    SELECT id, sort_key FROM filtered
+    ORDER BY
+        CASE WHEN :order = 'asc' THEN sort_key END ASC,
+        CASE WHEN :order = 'asc' THEN id END ASC,
+        CASE WHEN :order <> 'asc' THEN sort_key END DESC,
+        CASE WHEN :order <> 'asc' THEN id END DESC
+    LIMIT :limit OFFSET :offset
+)
+SELECT 
+    a.id, a.title, a.slug, CASE WHEN :with_content THEN a.content ELSE '' END, a.status, 
+    a.author_id, a.category_id, a.published_at, a.created_at, a.updated_at, a.version,
+    {{relation_columns}}
+FROM page p
+INNER JOIN articles a ON a.id = p.id
+{{relation_joins}}
+ORDER BY
+    CASE WHEN :order = 'asc' THEN p.sort_key END ASC,
+    CASE WHEN :order = 'asc' THEN p.id END ASC,
+    CASE WHEN :order <> 'asc' THEN p.sort_key END DESC,
+    CASE WHEN :order <> 'asc' THEN p.id END DESC{{tag_order}}
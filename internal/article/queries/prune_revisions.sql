@@ -0,0 +1,3 @@
+DELETE FROM article_revisions
+WHERE article_id = ?
+    AND revision <= (SELECT MAX(revision) FROM article_revisions WHERE article_id = ?) - ?
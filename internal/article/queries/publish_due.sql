@@ -0,0 +1,4 @@
+UPDATE articles 
+SET status = 'published', updated_at = ?, version = version + 1
+WHERE status = 'scheduled' AND deleted_at IS NULL AND julianday(published_at) <= julianday(?)
+RETURNING id
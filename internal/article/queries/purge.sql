@@ -0,0 +1,2 @@
+DELETE FROM articles WHERE id = ? AND deleted_at IS NOT NULL
+
@@ -0,0 +1,38 @@
+WITH hits AS (
+    SELECT
+        a.id,
+        bm25(articles_fts, 10.0, 1.0) AS rank,
+        highlight(articles_fts, 0, char(1), char(2)) AS title_highlight,
+        snippet(articles_fts, 1, char(1), char(2), '…', 32) AS snippet
+    FROM articles_fts
+    INNER JOIN articles a ON a.id = articles_fts.rowid
+    WHERE articles_fts MATCH ?
+        AND a.deleted_at IS NULL
+        AND (? = '' OR a.status = ?)
+        AND (? IS NULL OR EXISTS (
+            SELECT 1 FROM categories c_filter
+            WHERE c_filter.id = a.category_id AND c_filter.id = ? AND c_filter.deleted_at IS NULL
+        ))
+        AND (? IS NULL OR EXISTS (
+            SELECT 1 FROM article_tags at_filter
+            INNER JOIN tags t_filter ON t_filter.id = at_filter.tag_id AND t_filter.deleted_at IS NULL
+            WHERE at_filter.article_id = a.id AND at_filter.tag_id = ?
+        ))
+        -- trigram で検索できない2文字以下の語は LIKE で絞り込む
+        AND NOT EXISTS (
+            SELECT 1 FROM json_each(?) term
+            WHERE a.title NOT LIKE '%' || term.value || '%' ESCAPE '\'
+                AND a.content NOT LIKE '%' || term.value || '%' ESCAPE '\'
+        )
+    ORDER BY rank, a.id DESC
+    LIMIT ?
+)
+SELECT 
+    a.id, a.title, a.slug, a.status, 
+    a.author_id, a.category_id, a.published_at, a.created_at, a.updated_at,
+    h.rank, h.title_highlight, h.snippet, a.content,
+    {{relation_columns}}
+FROM hits h
+INNER JOIN articles a ON a.id = h.id
+{{relation_joins}}
+ORDER BY h.rank, a.id DESC{{tag_order}}
@@ -0,0 +1,31 @@
+WITH hits AS (
+    SELECT a.id, 0.0 AS rank, a.title AS title_highlight, '' AS snippet
+    FROM articles a
+    WHERE a.deleted_at IS NULL
+        AND (? = '' OR a.status = ?)
+        AND (? IS NULL OR EXISTS (
+            SELECT 1 FROM categories c_filter
+            WHERE c_filter.id = a.category_id AND c_filter.id = ? AND c_filter.deleted_at IS NULL
+        ))
+        AND (? IS NULL OR EXISTS (
+            SELECT 1 FROM article_tags at_filter
+            INNER JOIN tags t_filter ON t_filter.id = at_filter.tag_id AND t_filter.deleted_at IS NULL
+            WHERE at_filter.article_id = a.id AND at_filter.tag_id = ?
+        ))
+        AND NOT EXISTS (
+            SELECT 1 FROM json_each(?) term
+            WHERE a.title NOT LIKE '%' || term.value || '%' ESCAPE '\'
+                AND a.content NOT LIKE '%' || term.value || '%' ESCAPE '\'
+        )
+    ORDER BY a.updated_at DESC, a.id DESC
+    LIMIT ?
+)
+SELECT 
+    a.id, a.title, a.slug, a.status, 
+    a.author_id, a.category_id, a.published_at, a.created_at, a.updated_at,
+    h.rank, h.title_highlight, h.snippet, a.content,
+    {{relation_columns}}
+FROM hits h
+INNER JOIN articles a ON a.id = h.id
+{{relation_joins}}
+ORDER BY a.updated_at DESC, a.id DESC{{tag_order}}
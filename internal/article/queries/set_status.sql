@@ -0,0 +1,3 @@
+UPDATE articles 
+SET status = ?, published_at = ?, updated_at = ?, version = version + 1
+WHERE id = ? AND status = ? AND deleted_at IS NULL
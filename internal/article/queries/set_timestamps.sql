@@ -0,0 +1,3 @@
+UPDATE articles 
+SET created_at = ?, updated_at = ?, version = version + 1
+WHERE id = ?
@@ -0,0 +1 @@
+SELECT EXISTS (SELECT 1 FROM articles WHERE slug = ? AND deleted_at IS NOT NULL)
@@ -1,4 +0,0 @@
-UPDATE articles 
-SET status = 'draft', published_at = NULL, updated_at = ? 
-WHERE id = ?
-
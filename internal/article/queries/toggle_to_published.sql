@@ -1,4 +0,0 @@
-UPDATE articles 
-SET status = 'published', published_at = ?, updated_at = ? 
-WHERE id = ?
-
@@ -1,4 +1,3 @@
 UPDATE articles 
-SET title = ?, slug = ?, content = ?, status = ?, category_id = ?, updated_at = ?
-WHERE id = ?
-
+SET title = ?, slug = ?, content = ?, status = ?, category_id = ?, published_at = ?, updated_at = ?, version = version + 1
+WHERE id = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)
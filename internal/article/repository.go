@@ -2,17 +2,45 @@ package article
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
+	"cms/internal/category"
 	"cms/internal/tag"
+
+	"github.com/mattn/go-sqlite3"
 )
 
+// querier は *sql.DB と *sql.Tx に共通のメソッド（トランザクション内でも同じクエリを使う）
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
 type Repository struct {
-	db *sql.DB
+	db   querier
+	conn *sql.DB // トランザクションの開始用
 }
 
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, conn: db}
+}
+
+// InTx は fn にトランザクション内で使う Repository を渡して実行し、
+// fn がエラーを返せばロールバック、成功すればコミットする
+func (r *Repository) InTx(fn func(tx *Repository) error) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(&Repository{db: tx, conn: r.conn}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *Repository) GetAll() ([]Article, error) {
@@ -22,17 +50,72 @@ func (r *Repository) GetAll() ([]Article, error) {
 	}
 	defer rows.Close()
 
-	return r.scanArticlesWithTags(rows)
+	return r.scanArticlesWithTags(rows, IncludeAll)
+}
+
+// List は条件に一致する記事を返す（関連は p.Include で指定したものだけ読み込む）
+func (r *Repository) List(p ListParams) ([]Article, error) {
+	rows, err := r.db.Query(withRelations(queryList, p.Include), listArgs(p)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanArticlesWithTags(rows, p.Include)
+}
+
+// Count は条件に一致する記事の件数を返す（limit / offset は無視）
+func (r *Repository) Count(p ListParams) (int, error) {
+	var count int
+	err := r.db.QueryRow(queryCount, listArgs(p)...).Scan(&count)
+	return count, err
+}
+
+func listArgs(p ListParams) []any {
+	// SQLite の LIMIT -1 は無制限
+	limit := p.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	return []any{
+		sql.Named("status", p.Status),
+		sql.Named("category_id", p.CategoryID),
+		sql.Named("tag_id", p.TagID),
+		sql.Named("author_id", p.AuthorID),
+		sql.Named("created_from", sqlTime(p.CreatedFrom)),
+		sql.Named("created_to", sqlTime(p.CreatedTo)),
+		sql.Named("published_from", sqlTime(p.PublishedFrom)),
+		sql.Named("published_to", sqlTime(p.PublishedTo)),
+		sql.Named("sort", p.Sort),
+		sql.Named("order", p.Order),
+		sql.Named("limit", limit),
+		sql.Named("offset", p.Offset),
+		sql.Named("with_content", !p.WithoutContent),
+	}
+}
+
+// sqlTime は julianday() で比較できる UTC の文字列にする
+func sqlTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
 }
 
+// GetByID はすべての関連を含めて記事を返す
 func (r *Repository) GetByID(id int64) (*Article, error) {
-	rows, err := r.db.Query(queryGetByID, id)
+	return r.Get(id, IncludeAll)
+}
+
+// Get は inc で指定した関連だけを読み込んで記事を返す
+func (r *Repository) Get(id int64, inc Include) (*Article, error) {
+	rows, err := r.db.Query(withRelations(queryGetByID, inc), id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	articles, err := r.scanArticlesWithTags(rows)
+	articles, err := r.scanArticlesWithTags(rows, inc)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +132,7 @@ func (r *Repository) GetPublished() ([]Article, error) {
 	}
 	defer rows.Close()
 
-	return r.scanArticlesWithTags(rows)
+	return r.scanArticlesWithTags(rows, IncludeAll)
 }
 
 func (r *Repository) GetByCategory(categoryID int64) ([]Article, error) {
@@ -59,7 +142,7 @@ func (r *Repository) GetByCategory(categoryID int64) ([]Article, error) {
 	}
 	defer rows.Close()
 
-	return r.scanArticlesWithTags(rows)
+	return r.scanArticlesWithTags(rows, IncludeAll)
 }
 
 func (r *Repository) GetByTag(tagID int64) ([]Article, error) {
@@ -69,31 +152,126 @@ func (r *Repository) GetByTag(tagID int64) ([]Article, error) {
 	}
 	defer rows.Close()
 
-	return r.scanArticlesWithTags(rows)
+	return r.scanArticlesWithTags(rows, IncludeAll)
+}
+
+// FullTextSearch は全文検索のインデックス（FTS5）が使えるかどうか
+// （FTS5 なしでビルドした場合は起動時に同期用のトリガーが削除される）
+func (r *Repository) FullTextSearch() (bool, error) {
+	var count int
+	if err := r.db.QueryRow(queryFTSEnabled).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Search は全文検索。match は FTS5 の検索式、likeTerms は LIKE で絞り込む語の JSON 配列。
+// match が空の場合は LIKE だけで検索する（スコアは付かない）
+func (r *Repository) Search(match, likeTerms string, p SearchParams) ([]SearchResult, error) {
+	args := []any{
+		p.Status, p.Status,
+		p.CategoryID, p.CategoryID,
+		p.TagID, p.TagID,
+		likeTerms,
+		p.Limit,
+	}
+	query := querySearchLike
+	if match != "" {
+		args = append([]any{match}, args...)
+		query = querySearch
+	}
+
+	rows, err := r.db.Query(withRelations(query, p.Include), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resultMap := make(map[int64]*SearchResult)
+	var resultOrder []int64
+
+	for rows.Next() {
+		var res SearchResult
+		var rank float64
+		var rel relations
+		var tagID sql.NullInt64
+		var tagName, tagSlug sql.NullString
+		var tagCreatedAt sql.NullTime
+
+		dest := []any{
+			&res.ID, &res.Title, &res.Slug, &res.Status,
+			&res.AuthorID, &res.CategoryID, &res.PublishedAt, &res.CreatedAt, &res.UpdatedAt,
+			&rank, &res.TitleHighlight, &res.Snippet, &res.content,
+		}
+		dest = append(dest, rel.dest()...)
+		dest = append(dest, &tagID, &tagName, &tagSlug, &tagCreatedAt)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		existing, ok := resultMap[res.ID]
+		if !ok {
+			// bm25 は小さいほど関連度が高いので符号を反転する
+			if rank != 0 {
+				res.Score = -rank
+			}
+			res.Category, res.Author = rel.category(), rel.author()
+			if p.Include.Tags {
+				res.Tags = []tag.Tag{}
+			}
+			resultMap[res.ID] = &res
+			resultOrder = append(resultOrder, res.ID)
+			existing = &res
+		}
+
+		if tagID.Valid {
+			existing.Tags = append(existing.Tags, tag.Tag{
+				ID:        tagID.Int64,
+				Name:      tagName.String,
+				Slug:      tagSlug.String,
+				CreatedAt: tagCreatedAt.Time,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(resultOrder))
+	for _, id := range resultOrder {
+		results = append(results, *resultMap[id])
+	}
+	return results, nil
 }
 
-func (r *Repository) scanArticlesWithTags(rows *sql.Rows) ([]Article, error) {
+// scanArticlesWithTags は記事ごとにタグの行をまとめる（inc.Tags でなければタグは nil）
+func (r *Repository) scanArticlesWithTags(rows *sql.Rows, inc Include) ([]Article, error) {
 	articleMap := make(map[int64]*Article)
 	var articleOrder []int64
 
 	for rows.Next() {
 		var a Article
+		var rel relations
 		var tagID sql.NullInt64
 		var tagName, tagSlug sql.NullString
 		var tagCreatedAt sql.NullTime
 
-		err := rows.Scan(
+		dest := []any{
 			&a.ID, &a.Title, &a.Slug, &a.Content, &a.Status,
-			&a.AuthorID, &a.CategoryID, &a.PublishedAt, &a.CreatedAt, &a.UpdatedAt,
-			&tagID, &tagName, &tagSlug, &tagCreatedAt,
-		)
-		if err != nil {
+			&a.AuthorID, &a.CategoryID, &a.PublishedAt, &a.CreatedAt, &a.UpdatedAt, &a.Version,
+		}
+		dest = append(dest, rel.dest()...)
+		dest = append(dest, &tagID, &tagName, &tagSlug, &tagCreatedAt)
+		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
 
 		existing, ok := articleMap[a.ID]
 		if !ok {
-			a.Tags = []tag.Tag{}
+			a.Category, a.Author = rel.category(), rel.author()
+			if inc.Tags {
+				a.Tags = []tag.Tag{}
+			}
 			articleMap[a.ID] = &a
 			articleOrder = append(articleOrder, a.ID)
 			existing = &a
@@ -116,11 +294,88 @@ func (r *Repository) scanArticlesWithTags(rows *sql.Rows) ([]Article, error) {
 	return articles, nil
 }
 
-func (r *Repository) Create(title, slug, content, status string, authorID int64, categoryID *int64, tagIDs []int64) (*Article, error) {
+// withRelations はクエリの {{relation_columns}}・{{relation_joins}}・{{tag_order}} を inc に合わせて置き換える。
+// 含めない関連は JOIN せず、列は NULL にして Scan する列の順は変えない
+func withRelations(query string, inc Include) string {
+	var columns, joins []string
+	if inc.Category {
+		columns = append(columns, "c.id AS cat_id, c.name AS category_name, c.slug AS category_slug, c.created_at AS category_created_at, c.version AS category_version")
+		joins = append(joins, "LEFT JOIN categories c ON a.category_id = c.id AND c.deleted_at IS NULL")
+	} else {
+		columns = append(columns, "NULL, NULL, NULL, NULL, NULL")
+	}
+	if inc.Author {
+		columns = append(columns, "u.id AS user_id, u.name AS author_name")
+		joins = append(joins, "LEFT JOIN users u ON a.author_id = u.id")
+	} else {
+		columns = append(columns, "NULL, NULL")
+	}
+	tagOrder := ""
+	if inc.Tags {
+		columns = append(columns, "t.id AS tag_id, t.name AS tag_name, t.slug AS tag_slug, t.created_at AS tag_created_at")
+		joins = append(joins,
+			"LEFT JOIN article_tags at ON a.id = at.article_id",
+			"LEFT JOIN tags t ON at.tag_id = t.id AND t.deleted_at IS NULL",
+		)
+		tagOrder = ", t.id ASC"
+	} else {
+		columns = append(columns, "NULL, NULL, NULL, NULL")
+	}
+
+	return strings.NewReplacer(
+		"{{relation_columns}}", strings.Join(columns, ",\n    "),
+		"{{relation_joins}}", strings.Join(joins, "\n"),
+		"{{tag_order}}", tagOrder,
+	).Replace(query)
+}
+
+// relations は記事と一緒に JOIN して読み込むカテゴリ・著者の列（どちらも LEFT JOIN なので NULL になりうる）
+type relations struct {
+	categoryID                 sql.NullInt64
+	categoryName, categorySlug sql.NullString
+	categoryCreatedAt          sql.NullTime
+	categoryVersion            sql.NullInt64
+
+	authorID   sql.NullInt64
+	authorName sql.NullString
+}
+
+// dest はクエリの列の順に Scan の引数を返す
+func (rel *relations) dest() []any {
+	return []any{
+		&rel.categoryID, &rel.categoryName, &rel.categorySlug, &rel.categoryCreatedAt, &rel.categoryVersion,
+		&rel.authorID, &rel.authorName,
+	}
+}
+
+func (rel *relations) category() *category.Category {
+	if !rel.categoryID.Valid {
+		return nil
+	}
+	return &category.Category{
+		ID:        rel.categoryID.Int64,
+		Name:      rel.categoryName.String,
+		Slug:      rel.categorySlug.String,
+		CreatedAt: rel.categoryCreatedAt.Time,
+		Version:   rel.categoryVersion.Int64,
+	}
+}
+
+func (rel *relations) author() *Author {
+	if !rel.authorID.Valid {
+		return nil
+	}
+	return &Author{
+		ID:   rel.authorID.Int64,
+		Name: rel.authorName.String,
+	}
+}
+
+func (r *Repository) Create(title, slug, content, status string, authorID int64, categoryID *int64, tagIDs []int64, publishedAt *time.Time) (*Article, error) {
 	now := time.Now()
-	result, err := r.db.Exec(queryCreate, title, slug, content, status, authorID, categoryID, now, now)
+	result, err := r.db.Exec(queryCreate, title, slug, content, status, authorID, categoryID, publishedAt, now, now)
 	if err != nil {
-		return nil, err
+		return nil, r.uniqueError(err, slug)
 	}
 
 	id, err := result.LastInsertId()
@@ -135,10 +390,12 @@ func (r *Repository) Create(title, slug, content, status string, authorID int64,
 	return r.GetByID(id)
 }
 
-func (r *Repository) Update(id int64, title, slug, content, status string, categoryID *int64, tagIDs []int64) (*Article, error) {
-	_, err := r.db.Exec(queryUpdate, title, slug, content, status, categoryID, time.Now(), id)
+// Update は記事を更新する。version が 0 でなければそのバージョンの場合だけ更新する
+// （対象の行がなければ sql.ErrNoRows）
+func (r *Repository) Update(id int64, title, slug, content, status string, categoryID *int64, tagIDs []int64, publishedAt *time.Time, version int64) (*Article, error) {
+	err := execAffected(r.db.Exec(queryUpdate, title, slug, content, status, categoryID, publishedAt, time.Now(), id, version, version))
 	if err != nil {
-		return nil, err
+		return nil, r.uniqueError(err, slug)
 	}
 
 	if err := r.SetArticleTags(id, tagIDs); err != nil {
@@ -163,37 +420,214 @@ func (r *Repository) SetArticleTags(articleID int64, tagIDs []int64) error {
 	return nil
 }
 
-func (r *Repository) Publish(id int64) (*Article, error) {
-	now := time.Now()
-	_, err := r.db.Exec(queryPublish, now, now, id)
+// SetStatus はステータスと公開日時を変更する。
+// ステータスが from でなければ（他で変更された）sql.ErrNoRows
+func (r *Repository) SetStatus(id int64, from, to string, publishedAt *time.Time) (*Article, error) {
+	if err := execAffected(r.db.Exec(querySetStatus, to, publishedAt, time.Now(), id, from)); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// SetTimestamps は作成日時・更新日時を設定する（インポートで元の日時を残すため）
+func (r *Repository) SetTimestamps(id int64, createdAt, updatedAt time.Time) (*Article, error) {
+	_, err := r.db.Exec(querySetTimestamps, createdAt, updatedAt, id)
 	if err != nil {
 		return nil, err
 	}
 	return r.GetByID(id)
 }
 
-func (r *Repository) ToggleStatus(id int64) (*Article, error) {
-	// 現在の状態を取得
-	article, err := r.GetByID(id)
+// PublishDue は公開日時を過ぎた予約投稿を公開し、公開した記事の ID を返す
+func (r *Repository) PublishDue(now time.Time) ([]int64, error) {
+	rows, err := r.db.Query(queryPublishDue, now, now)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	now := time.Now()
-	if article.Status == "draft" {
-		// draft → published
-		_, err = r.db.Exec(queryToggleToPublished, now, now, id)
-	} else {
-		// published → draft
-		_, err = r.db.Exec(queryToggleToDraft, now, id)
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
+	return ids, rows.Err()
+}
+
+// Delete はゴミ箱に入れる（見つからなければ sql.ErrNoRows）
+func (r *Repository) Delete(id int64) error {
+	return execAffected(r.db.Exec(queryDelete, time.Now(), id))
+}
+
+// GetTrashed はゴミ箱の記事を新しく削除した順に返す（本文とタグは含まない）
+func (r *Repository) GetTrashed() ([]Article, error) {
+	rows, err := r.db.Query(queryGetTrashed)
 	if err != nil {
 		return nil, err
 	}
-	return r.GetByID(id)
+	defer rows.Close()
+
+	articles := []Article{}
+	for rows.Next() {
+		var a Article
+		err := rows.Scan(
+			&a.ID, &a.Title, &a.Slug, &a.Status,
+			&a.AuthorID, &a.CategoryID, &a.PublishedAt, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
 }
 
-func (r *Repository) Delete(id int64) error {
-	_, err := r.db.Exec(queryDelete, id)
+// Restore はゴミ箱から戻す
+func (r *Repository) Restore(id int64) error {
+	return execAffected(r.db.Exec(queryRestore, id))
+}
+
+// Purge はゴミ箱から完全に削除する
+func (r *Repository) Purge(id int64) error {
+	if err := execAffected(r.db.Exec(queryPurge, id)); err != nil {
+		return err
+	}
+	// SQLite は外部キー制約が無効なため、タグとの関連・リビジョン・ステータスの履歴は明示的に削除する
+	if _, err := r.db.Exec(queryDeleteTags, id); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec(queryDeleteRevisions, id); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(queryDeleteStatusHistory, id)
+	return err
+}
+
+// uniqueError は slug の UNIQUE 制約違反を ErrSlugTaken にする
+// （ゴミ箱の記事が使っている場合は、戻すか完全に削除すれば使えるので ErrSlugInTrash）
+func (r *Repository) uniqueError(err error, slug string) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.ExtendedCode != sqlite3.ErrConstraintUnique {
+		return err
+	}
+	var trashed bool
+	if err := r.db.QueryRow(querySlugInTrash, slug).Scan(&trashed); err != nil {
+		return err
+	}
+	if trashed {
+		return ErrSlugInTrash
+	}
+	return ErrSlugTaken
+}
+
+// execAffected は対象の行がなければ sql.ErrNoRows を返す
+func execAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CreateRevision は記事の現在の内容をリビジョンとして保存
+func (r *Repository) CreateRevision(a *Article) error {
+	tagIDs := make([]int64, 0, len(a.Tags))
+	for _, t := range a.Tags {
+		tagIDs = append(tagIDs, t.ID)
+	}
+	data, err := json.Marshal(tagIDs)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(queryCreateRevision,
+		a.ID, a.Title, a.Slug, a.Content, a.Status, a.CategoryID, string(data), time.Now(),
+		a.ID,
+	)
+	return err
+}
+
+// PruneRevisions は新しいものから keep 件を残して古いリビジョンを削除
+func (r *Repository) PruneRevisions(articleID int64, keep int) error {
+	_, err := r.db.Exec(queryPruneRevisions, articleID, articleID, keep)
+	return err
+}
+
+// GetRevisions はリビジョンの一覧を新しい順に返す（本文は含まない）
+func (r *Repository) GetRevisions(articleID int64) ([]Revision, error) {
+	rows, err := r.db.Query(queryGetRevisions, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []Revision{}
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *Repository) GetRevision(articleID, revision int64) (*Revision, error) {
+	return scanRevision(r.db.QueryRow(queryGetRevision, articleID, revision))
+}
+
+func (r *Repository) GetLatestRevision(articleID int64) (*Revision, error) {
+	return scanRevision(r.db.QueryRow(queryGetLatestRevision, articleID))
+}
+
+func scanRevision(row interface{ Scan(...any) error }) (*Revision, error) {
+	var rev Revision
+	var tagIDs string
+	err := row.Scan(
+		&rev.ID, &rev.ArticleID, &rev.Revision, &rev.Title, &rev.Slug, &rev.Content,
+		&rev.Status, &rev.CategoryID, &tagIDs, &rev.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(tagIDs), &rev.TagIDs); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// CreateStatusChange はステータスの変更を履歴に記録する（from が空なら作成、userID が nil ならシステム）
+func (r *Repository) CreateStatusChange(articleID int64, from, to string, userID *int64, comment string) error {
+	fromStatus := sql.NullString{String: from, Valid: from != ""}
+	_, err := r.db.Exec(queryCreateStatusChange, articleID, fromStatus, to, userID, comment, time.Now())
 	return err
 }
+
+// GetStatusHistory はステータスの変更履歴を新しい順に返す
+func (r *Repository) GetStatusHistory(articleID int64) ([]StatusChange, error) {
+	rows, err := r.db.Query(queryGetStatusHistory, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []StatusChange{}
+	for rows.Next() {
+		var h StatusChange
+		err := rows.Scan(&h.ID, &h.ArticleID, &h.FromStatus, &h.ToStatus, &h.UserID, &h.UserName, &h.Comment, &h.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
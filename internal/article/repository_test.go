@@ -0,0 +1,58 @@
+package article
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithRelations(t *testing.T) {
+	tests := []struct {
+		name    string
+		inc     Include
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "none",
+			inc:     Include{},
+			want:    []string{"NULL, NULL, NULL, NULL, NULL,\n    NULL, NULL,\n    NULL, NULL, NULL, NULL\nFROM"},
+			notWant: []string{"LEFT JOIN categories", "LEFT JOIN users", "LEFT JOIN article_tags", "LEFT JOIN tags", "t.id ASC"},
+		},
+		{
+			name:    "tags",
+			inc:     DefaultInclude,
+			want:    []string{"LEFT JOIN tags t", "t.id AS tag_id"},
+			notWant: []string{"LEFT JOIN categories", "LEFT JOIN users"},
+		},
+		{
+			name:    "author and category",
+			inc:     Include{Author: true, Category: true},
+			want:    []string{"LEFT JOIN categories c", "LEFT JOIN users u", "c.id AS cat_id", "u.id AS user_id"},
+			notWant: []string{"LEFT JOIN article_tags", "LEFT JOIN tags", "t.id ASC"},
+		},
+		{
+			name: "all",
+			inc:  IncludeAll,
+			want: []string{"LEFT JOIN categories c", "LEFT JOIN users u", "LEFT JOIN tags t", "ORDER BY h.rank, a.id DESC, t.id ASC"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := withRelations(querySearch, tt.inc)
+			if strings.Contains(query, "{{") {
+				t.Errorf("placeholder is left in the query:\n%s", query)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(query, want) {
+					t.Errorf("query does not contain %q:\n%s", want, query)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(query, notWant) {
+					t.Errorf("query contains %q:\n%s", notWant, query)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package article
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// revisionText は diff を取るためにリビジョンをテキストにする（メタデータのあとに本文）
+func revisionText(r *Revision) string {
+	category := "-"
+	if r.CategoryID != nil {
+		category = fmt.Sprint(*r.CategoryID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "title: %s\n", r.Title)
+	fmt.Fprintf(&b, "slug: %s\n", r.Slug)
+	fmt.Fprintf(&b, "status: %s\n", r.Status)
+	fmt.Fprintf(&b, "category_id: %s\n", category)
+	fmt.Fprintf(&b, "tag_ids: %v\n", r.TagIDs)
+	b.WriteString("\n")
+	b.WriteString(r.Content)
+	if !strings.HasSuffix(r.Content, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// diffRevisions は2つのリビジョンの unified diff を返す（差分がなければ空文字）
+func diffRevisions(from, to *Revision) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(revisionText(from)),
+		B:        difflib.SplitLines(revisionText(to)),
+		FromFile: fmt.Sprintf("revision %d", from.Revision),
+		ToFile:   fmt.Sprintf("revision %d", to.Revision),
+		Context:  3,
+	})
+}
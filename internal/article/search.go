@@ -0,0 +1,122 @@
+package article
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// trigram トークナイザは3文字未満の語を検索できない
+const minTrigramLength = 3
+
+// snippetRadius は LIKE で検索した場合の抜粋の前後の文字数
+const snippetRadius = 32
+
+// FTS5 の highlight / snippet で一致箇所の前後に入れる印（エスケープ後に <mark> に置き換える）
+const (
+	markStart = "\x01"
+	markEnd   = "\x02"
+)
+
+// searchTerms は検索語を FTS5 の検索式と LIKE で絞り込む語（JSON 配列）に分ける。
+// 空白区切りの語はすべて含む記事（AND）を検索する。fts が false ならすべて LIKE で検索する
+func searchTerms(q string, fts bool) (match string, likeTerms string, terms []string) {
+	var phrases, likes []string
+	for _, term := range strings.Fields(q) {
+		terms = append(terms, term)
+		if fts && utf8.RuneCountInString(term) >= minTrigramLength {
+			// フレーズとして渡し、記号などを検索式の構文として解釈させない
+			phrases = append(phrases, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`)
+			continue
+		}
+		likes = append(likes, escapeLike(term))
+	}
+
+	data, _ := json.Marshal(append([]string{}, likes...))
+	return strings.Join(phrases, " "), string(data), terms
+}
+
+// escapeLike は LIKE のワイルドカードをエスケープ（ESCAPE '\'）
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
+
+// markHighlight は印の付いた文字列を HTML エスケープし、印を <mark> に置き換える
+func markHighlight(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, markStart, "<mark>")
+	return strings.ReplaceAll(s, markEnd, "</mark>")
+}
+
+// likeSnippet は最初に一致した箇所の前後を抜き出し、一致箇所に印を付ける
+func likeSnippet(text string, terms []string) string {
+	src := []rune(text)
+	matched, first := matchTerms(src, terms)
+
+	start, end := 0, len(src)
+	if first >= 0 {
+		start = max(first-snippetRadius, 0)
+		end = min(first+snippetRadius, len(src))
+	} else if end > snippetRadius*2 {
+		end = snippetRadius * 2
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	writeMarked(&b, src, matched, start, end)
+	if end < len(src) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// likeHighlight は文字列全体の一致箇所に印を付ける
+func likeHighlight(text string, terms []string) string {
+	src := []rune(text)
+	matched, _ := matchTerms(src, terms)
+
+	var b strings.Builder
+	writeMarked(&b, src, matched, 0, len(src))
+	return b.String()
+}
+
+// matchTerms は各文字が検索語に一致したかと、最初に一致した位置を返す
+// （LIKE と同様に大文字・小文字を区別しない）
+func matchTerms(src []rune, terms []string) ([]bool, int) {
+	lower := []rune(strings.Map(unicode.ToLower, string(src)))
+	matched := make([]bool, len(src))
+	first := -1
+	for _, term := range terms {
+		t := strings.Map(unicode.ToLower, term)
+		n := utf8.RuneCountInString(t)
+		for i := 0; i+n <= len(lower); i++ {
+			if string(lower[i:i+n]) != t {
+				continue
+			}
+			for j := i; j < i+n; j++ {
+				matched[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+	return matched, first
+}
+
+func writeMarked(b *strings.Builder, src []rune, matched []bool, start, end int) {
+	for i := start; i < end; i++ {
+		if matched[i] && (i == start || !matched[i-1]) {
+			b.WriteString(markStart)
+		}
+		b.WriteRune(src[i])
+		if matched[i] && (i == end-1 || !matched[i+1]) {
+			b.WriteString(markEnd)
+		}
+	}
+}
@@ -1,42 +1,514 @@
 package article
 
-import "database/sql"
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"cms/internal/etag"
+	"cms/internal/settings"
+	"cms/internal/user"
+)
 
 type Service struct {
-	repo *Repository
+	repo            *Repository
+	userRepo        *user.Repository
+	settingsService *settings.Service
 }
 
 func NewService(db *sql.DB) *Service {
-	return &Service{repo: NewRepository(db)}
+	return &Service{repo: NewRepository(db), userRepo: user.NewRepository(db), settingsService: settings.NewService()}
 }
 
 func (s *Service) GetAll() ([]Article, error) {
 	return s.repo.GetAll()
 }
 
+// List は条件に一致する記事と、ページングする前の件数を返す
+func (s *Service) List(p ListParams) ([]Article, int, error) {
+	articles, err := s.repo.List(p)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.repo.Count(p)
+	if err != nil {
+		return nil, 0, err
+	}
+	return articles, total, nil
+}
+
 func (s *Service) GetByID(id int64) (*Article, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *Service) Create(title, slug, content, status string, authorID int64, categoryID *int64, tagIDs []int64) (*Article, error) {
+// Get は inc で指定した関連だけを読み込んで記事を返す
+func (s *Service) Get(id int64, inc Include) (*Article, error) {
+	return s.repo.Get(id, inc)
+}
+
+// Search は記事を全文検索し、関連度の高い順に返す
+func (s *Service) Search(p SearchParams) ([]SearchResult, error) {
+	fts, err := s.repo.FullTextSearch()
+	if err != nil {
+		return nil, err
+	}
+	match, likeTerms, terms := searchTerms(p.Query, fts)
+
+	results, err := s.repo.Search(match, likeTerms, p)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		res := &results[i]
+		if match == "" {
+			// LIKE だけで検索した場合は抜粋をここで作る
+			res.TitleHighlight = likeHighlight(res.Title, terms)
+			res.Snippet = likeSnippet(res.content, terms)
+		}
+		res.TitleHighlight = markHighlight(res.TitleHighlight)
+		res.Snippet = markHighlight(res.Snippet)
+	}
+	return results, nil
+}
+
+// Create は記事を作成する。下書き以外で作成する場合は、下書きからそのステータスまで
+// Transition と同じ遷移をしたものとして権限を確認し、履歴にも記録する
+// （作成者の権限では自分を作成者にした下書きか、レビュー待ちの記事しか作れない）
+func (s *Service) Create(actor *user.User, title, slug, content, status string, authorID int64, categoryID *int64, tagIDs []int64, publishedAt *time.Time) (*Article, error) {
 	if status == "" {
-		status = "draft"
+		status = StatusDraft
+	}
+	path, err := checkInitialStatus(actor, authorID, status)
+	if err != nil {
+		return nil, err
+	}
+	publishedAt, err = resolvePublishedAt(status, publishedAt, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// 作成者は存在するユーザーでなければならない
+	if _, err := s.userRepo.GetByID(authorID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidAuthor
+		}
+		return nil, err
+	}
+
+	// 記事・タグ・ステータスの履歴・リビジョンはまとめて保存する
+	var article *Article
+	err = s.repo.InTx(func(tx *Repository) error {
+		var err error
+		article, err = tx.Create(title, slug, content, status, authorID, categoryID, tagIDs, publishedAt)
+		if err != nil {
+			return err
+		}
+		from := ""
+		for _, to := range path {
+			if err := recordStatus(tx, actor, article.ID, from, to, ""); err != nil {
+				return err
+			}
+			from = to
+		}
+		return s.saveRevision(tx, article)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// Update は記事を更新する。publishedAt を省略した場合は元の公開日時を保ち、
+// resetPublishedAt なら公開日時を付け直す（公開なら現在時刻、下書きなら未設定）。
+// ステータスを変える場合は Transition と同じ遷移しかできない（差し戻しは Transition でコメントを付ける）。
+// version が 0 でなければ、記事がそのバージョンのときだけ更新する（違えば etag.ErrMismatch）
+func (s *Service) Update(actor *user.User, id int64, title, slug, content, status string, categoryID *int64, tagIDs []int64, publishedAt *time.Time, resetPublishedAt bool, version int64) (*Article, error) {
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := canWrite(actor, current.AuthorID, current.Status); err != nil {
+		return nil, err
+	}
+	if version != 0 && current.Version != version {
+		return nil, etag.ErrMismatch
+	}
+	if status == "" {
+		status = current.Status
+	}
+	if status != current.Status {
+		if err := checkTransition(actor, current, status, ""); err != nil {
+			return nil, err
+		}
+	}
+	if status == current.Status && publishedAt == nil && !resetPublishedAt {
+		// ステータスも公開日時も変えない場合は検証し直さない（予定日時を過ぎた予約投稿など）
+		publishedAt = current.PublishedAt
+	} else {
+		publishedAt, err = resolvePublishedAt(status, publishedAt, current, resetPublishedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 記事・タグ・ステータスの履歴・リビジョンはまとめて保存する（途中で失敗したら何も変えない）
+	var article *Article
+	err = s.repo.InTx(func(tx *Repository) error {
+		var err error
+		article, err = tx.Update(id, title, slug, content, status, categoryID, tagIDs, publishedAt, version)
+		if err == sql.ErrNoRows && version != 0 {
+			// 取得してから更新するまでの間に他で更新された
+			return etag.ErrMismatch
+		}
+		if err != nil {
+			return err
+		}
+		if status != current.Status {
+			if err := recordStatus(tx, actor, id, current.Status, status, ""); err != nil {
+				return err
+			}
+		}
+		return s.saveRevision(tx, article)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// Transition は記事のステータスを変更する。差し戻し（レビュー中・レビュー済みから下書き）には comment が必要。
+// 下書きのレビュー依頼は作成者もできるが、それ以外の変更は編集者以上のみ
+func (s *Service) Transition(actor *user.User, id int64, status, comment string, publishedAt *time.Time) (*Article, error) {
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTransition(actor, current, status, comment); err != nil {
+		return nil, err
+	}
+	publishedAt, err = resolvePublishedAt(status, publishedAt, current, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.setStatus(actor, current, status, strings.TrimSpace(comment), publishedAt)
+}
+
+// setStatus はステータスを変更して履歴に記録する
+func (s *Service) setStatus(actor *user.User, current *Article, status, comment string, publishedAt *time.Time) (*Article, error) {
+	var article *Article
+	err := s.repo.InTx(func(tx *Repository) error {
+		var err error
+		article, err = tx.SetStatus(current.ID, current.Status, status, publishedAt)
+		if err == sql.ErrNoRows {
+			// 取得してから変更するまでの間に他でステータスが変わった（ゴミ箱に入れられた場合を含む）
+			return fmt.Errorf("%w: status was changed by another request", ErrInvalidTransition)
+		}
+		if err != nil {
+			return err
+		}
+		return recordStatus(tx, actor, current.ID, current.Status, status, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// recordStatus はステータスの変更を履歴に記録する（actor が System なら変更したユーザーは記録しない）
+func recordStatus(repo *Repository, actor *user.User, id int64, from, to, comment string) error {
+	var userID *int64
+	if actor != nil && actor.ID != 0 {
+		userID = &actor.ID
+	}
+	return repo.CreateStatusChange(id, from, to, userID, comment)
+}
+
+// checkTransition は current から status に変更できるか確認する
+func checkTransition(actor *user.User, current *Article, status, comment string) error {
+	if !ValidStatus(status) {
+		return ErrInvalidStatus
+	}
+	if !slices.Contains(transitions[current.Status], status) {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidTransition, current.Status, status)
+	}
+	if current.Status == StatusDraft && status == StatusInReview {
+		// レビュー依頼は自分の下書きなら作成者もできる
+		if err := canWrite(actor, current.AuthorID, current.Status); err != nil {
+			return err
+		}
+	} else if err := user.RequireEditor(actor); err != nil {
+		return err
+	}
+	if isRejection(current.Status, status) && strings.TrimSpace(comment) == "" {
+		return ErrCommentRequired
+	}
+	return nil
+}
+
+// checkInitialStatus は作成時のステータスにできるか、下書きからの遷移を1つずつ確認し、
+// 履歴に記録するステータスを下書きから順に返す
+func checkInitialStatus(actor *user.User, authorID int64, status string) ([]string, error) {
+	if !ValidStatus(status) {
+		return nil, ErrInvalidStatus
+	}
+	if err := canWrite(actor, authorID, StatusDraft); err != nil {
+		return nil, err
+	}
+	path := statusPath(StatusDraft, status)
+	for i := 1; i < len(path); i++ {
+		if err := checkTransition(actor, &Article{AuthorID: authorID, Status: path[i-1]}, path[i], ""); err != nil {
+			return nil, err
+		}
+	}
+	return path, nil
+}
+
+// statusPath は from から to までの最短の遷移を from も含めて返す（たどれなければ nil）
+func statusPath(from, to string) []string {
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		status := queue[0]
+		queue = queue[1:]
+		if status == to {
+			path := []string{to}
+			for status != from {
+				status = prev[status]
+				path = append([]string{status}, path...)
+			}
+			return path
+		}
+		for _, next := range transitions[status] {
+			if _, ok := prev[next]; !ok {
+				prev[next] = status
+				queue = append(queue, next)
+			}
+		}
 	}
-	return s.repo.Create(title, slug, content, status, authorID, categoryID, tagIDs)
+	return nil
 }
 
-func (s *Service) Update(id int64, title, slug, content, status string, categoryID *int64, tagIDs []int64) (*Article, error) {
-	return s.repo.Update(id, title, slug, content, status, categoryID, tagIDs)
+// canWrite は記事を作成・変更・削除できるか確認する。
+// 編集者以上はすべての記事、作成者の権限では自分の下書きだけ
+func canWrite(actor *user.User, authorID int64, status string) error {
+	if actor.IsEditor() {
+		return nil
+	}
+	if actor == nil || actor.Role != user.RoleAuthor || actor.ID != authorID || status != StatusDraft {
+		return user.ErrForbidden
+	}
+	return nil
+}
+
+// resolvePublishedAt はステータスに応じた公開日時を返す。
+// 指定があればその日時（予約投稿は未来の日時が必須）、なければ元の記事の公開日時を引き継ぐ
+func resolvePublishedAt(status string, publishedAt *time.Time, current *Article, reset bool) (*time.Time, error) {
+	switch status {
+	case StatusScheduled:
+		if publishedAt == nil || !publishedAt.After(time.Now()) {
+			return nil, ErrInvalidSchedule
+		}
+		return publishedAt, nil
+	case StatusPublished:
+		if publishedAt != nil {
+			return publishedAt, nil
+		}
+		t := publishDate(current, reset)
+		return &t, nil
+	case StatusDraft, StatusInReview, StatusApproved:
+		if publishedAt != nil {
+			return publishedAt, nil
+		}
+		if current == nil || reset {
+			return nil, nil
+		}
+		return current.PublishedAt, nil
+	}
+	return nil, ErrInvalidStatus
+}
+
+// publishDate は公開するときの日時を返す。以前に公開したことがあれば元の公開日時
+// （予約を取り消した記事などの未来の日時は除く）、なければ・reset なら現在時刻
+func publishDate(a *Article, reset bool) time.Time {
+	now := time.Now()
+	if a == nil || reset || a.PublishedAt == nil || a.PublishedAt.After(now) {
+		return now
+	}
+	return *a.PublishedAt
+}
+
+// PublishDue は公開日時を過ぎた予約投稿を公開し、公開した記事の ID を返す
+func (s *Service) PublishDue() ([]int64, error) {
+	var ids []int64
+	err := s.repo.InTx(func(tx *Repository) error {
+		var err error
+		ids, err = tx.PublishDue(time.Now())
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := recordStatus(tx, user.System, id, StatusScheduled, StatusPublished, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// saveRevision は記事の内容をリビジョンとして保存し、保持数を超えた古いリビジョンを削除
+func (s *Service) saveRevision(repo *Repository, article *Article) error {
+	if err := repo.CreateRevision(article); err != nil {
+		return err
+	}
+
+	cfg, err := s.settingsService.Get()
+	if err != nil {
+		return err
+	}
+	if cfg.RevisionRetention > 0 {
+		return repo.PruneRevisions(article.ID, cfg.RevisionRetention)
+	}
+	return nil
+}
+
+// GetRevisions は記事のリビジョン一覧を返す（記事がなければ sql.ErrNoRows）
+func (s *Service) GetRevisions(id int64) ([]Revision, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRevisions(id)
+}
+
+func (s *Service) GetRevision(id, revision int64) (*Revision, error) {
+	return s.repo.GetRevision(id, revision)
+}
+
+// DiffRevisions はリビジョン from から to への unified diff を返す（to が 0 なら最新のリビジョン）
+func (s *Service) DiffRevisions(id, from, to int64) (*RevisionDiff, error) {
+	a, err := s.repo.GetRevision(id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var b *Revision
+	if to == 0 {
+		b, err = s.repo.GetLatestRevision(id)
+	} else {
+		b, err = s.repo.GetRevision(id, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := diffRevisions(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return &RevisionDiff{From: a.Revision, To: b.Revision, Diff: diff}, nil
+}
+
+// RestoreRevision はリビジョンの内容で記事を更新する（新しいリビジョンとして保存される）。
+// 公開状態と公開日時は変更しない。version は Update と同じ
+func (s *Service) RestoreRevision(actor *user.User, id, revision, version int64) (*Article, error) {
+	rev, err := s.repo.GetRevision(id, revision)
+	if err != nil {
+		return nil, err
+	}
+	return s.Update(actor, id, rev.Title, rev.Slug, rev.Content, "", rev.CategoryID, rev.TagIDs, nil, false, version)
+}
+
+// Publish はレビュー済みか予約投稿の記事を公開する（以前に公開したことがあれば元の公開日時のまま）。編集者以上のみ
+func (s *Service) Publish(actor *user.User, id int64) (*Article, error) {
+	return s.Transition(actor, id, StatusPublished, "", nil)
+}
+
+// ToggleStatus は公開・非公開を切り替える。公開済み・予約投稿は下書きに戻し、レビュー済みは公開する
+// （下書き・レビュー中の記事はレビューを経ないと公開できない）。
+// 公開日時は下書きに戻しても残し、再公開では resetPublishedAt でなければ元の日時を使う。編集者以上のみ
+func (s *Service) ToggleStatus(actor *user.User, id int64, resetPublishedAt bool) (*Article, error) {
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var status string
+	var publishedAt *time.Time
+	switch current.Status {
+	case StatusPublished, StatusScheduled:
+		status = StatusDraft
+		publishedAt = current.PublishedAt
+	default:
+		status = StatusPublished
+		t := publishDate(current, resetPublishedAt)
+		publishedAt = &t
+	}
+	if err := checkTransition(actor, current, status, ""); err != nil {
+		return nil, err
+	}
+	return s.setStatus(actor, current, status, "", publishedAt)
+}
+
+// GetStatusHistory は記事のステータスの変更履歴を返す（記事がなければ sql.ErrNoRows）
+func (s *Service) GetStatusHistory(id int64) ([]StatusChange, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetStatusHistory(id)
 }
 
-func (s *Service) Publish(id int64) (*Article, error) {
-	return s.repo.Publish(id)
+// ReviewQueue はレビュー待ちの記事を返す（p のステータスは無視する）
+func (s *Service) ReviewQueue(p ListParams) ([]Article, int, error) {
+	p.Status = StatusInReview
+	return s.List(p)
 }
 
-func (s *Service) ToggleStatus(id int64) (*Article, error) {
-	return s.repo.ToggleStatus(id)
+// SetTimestamps は作成日時・更新日時を設定する（インポート用）。作成者の権限では自分の下書きだけ
+func (s *Service) SetTimestamps(actor *user.User, id int64, createdAt, updatedAt time.Time) (*Article, error) {
+	article, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := canWrite(actor, article.AuthorID, article.Status); err != nil {
+		return nil, err
+	}
+	return s.repo.SetTimestamps(id, createdAt, updatedAt)
 }
 
-func (s *Service) Delete(id int64) error {
+// Delete はゴミ箱に入れる。作成者の権限では自分の下書きだけ
+func (s *Service) Delete(actor *user.User, id int64) error {
+	article, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := canWrite(actor, article.AuthorID, article.Status); err != nil {
+		return err
+	}
 	return s.repo.Delete(id)
 }
+
+func (s *Service) GetTrashed() ([]Article, error) {
+	return s.repo.GetTrashed()
+}
+
+// Restore はゴミ箱から戻す。編集者以上のみ
+func (s *Service) Restore(actor *user.User, id int64) error {
+	if err := user.RequireEditor(actor); err != nil {
+		return err
+	}
+	return s.repo.Restore(id)
+}
+
+// Purge は完全に削除する。編集者以上のみ
+func (s *Service) Purge(actor *user.User, id int64) error {
+	if err := user.RequireEditor(actor); err != nil {
+		return err
+	}
+	return s.repo.Purge(id)
+}
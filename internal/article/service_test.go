@@ -0,0 +1,130 @@
+package article
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"cms/internal/user"
+)
+
+func TestCheckTransition(t *testing.T) {
+	author := &user.User{ID: 1, Role: user.RoleAuthor}
+	editor := &user.User{ID: 2, Role: user.RoleEditor}
+	admin := &user.User{ID: 3, Role: user.RoleAdmin}
+
+	tests := []struct {
+		name     string
+		actor    *user.User
+		authorID int64
+		from     string
+		to       string
+		comment  string
+		wantErr  error
+	}{
+		{name: "author requests review of own draft", actor: author, authorID: 1, from: StatusDraft, to: StatusInReview},
+		{name: "author requests review of other's draft", actor: author, authorID: 9, from: StatusDraft, to: StatusInReview, wantErr: user.ErrForbidden},
+		{name: "editor requests review of other's draft", actor: editor, authorID: 9, from: StatusDraft, to: StatusInReview},
+		{name: "author approves", actor: author, authorID: 1, from: StatusInReview, to: StatusApproved, wantErr: user.ErrForbidden},
+		{name: "author publishes", actor: author, authorID: 1, from: StatusApproved, to: StatusPublished, wantErr: user.ErrForbidden},
+		{name: "anonymous", actor: nil, authorID: 1, from: StatusDraft, to: StatusInReview, wantErr: user.ErrForbidden},
+		{name: "editor approves", actor: editor, authorID: 1, from: StatusInReview, to: StatusApproved},
+		{name: "editor rejects without comment", actor: editor, authorID: 1, from: StatusInReview, to: StatusDraft, wantErr: ErrCommentRequired},
+		{name: "editor rejects with blank comment", actor: editor, authorID: 1, from: StatusApproved, to: StatusDraft, comment: "  ", wantErr: ErrCommentRequired},
+		{name: "editor rejects with comment", actor: editor, authorID: 1, from: StatusInReview, to: StatusDraft, comment: "fix typos"},
+		{name: "admin publishes", actor: admin, authorID: 1, from: StatusApproved, to: StatusPublished},
+		{name: "editor schedules", actor: editor, authorID: 1, from: StatusApproved, to: StatusScheduled},
+		{name: "editor unpublishes without comment", actor: editor, authorID: 1, from: StatusPublished, to: StatusDraft},
+		{name: "draft cannot be published", actor: admin, authorID: 1, from: StatusDraft, to: StatusPublished, wantErr: ErrInvalidTransition},
+		{name: "review cannot be skipped", actor: admin, authorID: 1, from: StatusInReview, to: StatusPublished, wantErr: ErrInvalidTransition},
+		{name: "same status", actor: admin, authorID: 1, from: StatusApproved, to: StatusApproved, wantErr: ErrInvalidTransition},
+		{name: "unknown status", actor: admin, authorID: 1, from: StatusDraft, to: "archived", wantErr: ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := &Article{AuthorID: tt.authorID, Status: tt.from}
+			err := checkTransition(tt.actor, current, tt.to, tt.comment)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    *user.User
+		authorID int64
+		status   string
+		wantErr  bool
+	}{
+		{name: "author own draft", actor: &user.User{ID: 1, Role: user.RoleAuthor}, authorID: 1, status: StatusDraft},
+		{name: "author own article in review", actor: &user.User{ID: 1, Role: user.RoleAuthor}, authorID: 1, status: StatusInReview, wantErr: true},
+		{name: "author own published article", actor: &user.User{ID: 1, Role: user.RoleAuthor}, authorID: 1, status: StatusPublished, wantErr: true},
+		{name: "author other's draft", actor: &user.User{ID: 1, Role: user.RoleAuthor}, authorID: 2, status: StatusDraft, wantErr: true},
+		{name: "editor other's published article", actor: &user.User{ID: 1, Role: user.RoleEditor}, authorID: 2, status: StatusPublished},
+		{name: "admin other's article in review", actor: &user.User{ID: 1, Role: user.RoleAdmin}, authorID: 2, status: StatusInReview},
+		{name: "anonymous", actor: nil, authorID: 0, status: StatusDraft, wantErr: true},
+		{name: "unknown role", actor: &user.User{ID: 1, Role: "guest"}, authorID: 1, status: StatusDraft, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := canWrite(tt.actor, tt.authorID, tt.status)
+			if tt.wantErr != errors.Is(err, user.ErrForbidden) {
+				t.Errorf("err = %v, want forbidden = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckInitialStatus(t *testing.T) {
+	author := &user.User{ID: 1, Role: user.RoleAuthor}
+	editor := &user.User{ID: 2, Role: user.RoleEditor}
+
+	tests := []struct {
+		name     string
+		actor    *user.User
+		authorID int64
+		status   string
+		wantPath []string
+		wantErr  error
+	}{
+		{name: "author creates draft", actor: author, authorID: 1, status: StatusDraft, wantPath: []string{StatusDraft}},
+		{name: "author creates for review", actor: author, authorID: 1, status: StatusInReview, wantPath: []string{StatusDraft, StatusInReview}},
+		{name: "author creates for other", actor: author, authorID: 9, status: StatusDraft, wantErr: user.ErrForbidden},
+		{name: "author creates approved", actor: author, authorID: 1, status: StatusApproved, wantErr: user.ErrForbidden},
+		{name: "author creates published", actor: author, authorID: 1, status: StatusPublished, wantErr: user.ErrForbidden},
+		{name: "editor creates published", actor: editor, authorID: 1, status: StatusPublished,
+			wantPath: []string{StatusDraft, StatusInReview, StatusApproved, StatusPublished}},
+		{name: "editor creates scheduled", actor: editor, authorID: 1, status: StatusScheduled,
+			wantPath: []string{StatusDraft, StatusInReview, StatusApproved, StatusScheduled}},
+		{name: "unknown status", actor: editor, authorID: 1, status: "archived", wantErr: ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := checkInitialStatus(tt.actor, tt.authorID, tt.status)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !slices.Equal(path, tt.wantPath) {
+				t.Errorf("path = %v, want %v", path, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cms/internal/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(db *sql.DB) *Handler {
+	return &Handler{service: NewService(db)}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/auth/login", h.Login)
+	r.POST("/auth/logout", h.Logout)
+	r.GET("/auth/me", h.Me)
+	r.GET("/auth/tokens", h.GetTokens)
+	r.POST("/auth/tokens", h.CreateToken)
+	r.DELETE("/auth/tokens/:id", h.RevokeToken)
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type TokenRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// Login はセッションを作り、HttpOnly の Cookie で返す
+func (h *Handler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, u, expiresAt, err := h.service.Login(req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setSessionCookie(c, token, int(time.Until(expiresAt).Seconds()))
+	c.JSON(http.StatusOK, u)
+}
+
+func (h *Handler) Logout(c *gin.Context) {
+	if token, err := c.Cookie(sessionCookie); err == nil && token != "" {
+		if err := h.service.Logout(token); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	setSessionCookie(c, "", -1)
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Me はログイン中のユーザーを返す
+func (h *Handler) Me(c *gin.Context) {
+	u, ok := RequireUser(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, u)
+}
+
+// GetTokens はログイン中のユーザーの API トークン一覧を返す（トークン自体は含まない）
+func (h *Handler) GetTokens(c *gin.Context) {
+	u, ok := RequireUser(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.service.GetTokens(u.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *Handler) CreateToken(c *gin.Context) {
+	u, ok := RequireUser(c)
+	if !ok {
+		return
+	}
+
+	var req TokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := h.service.CreateToken(u.ID, req.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, token)
+}
+
+func (h *Handler) RevokeToken(c *gin.Context) {
+	u, ok := RequireUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.service.RevokeToken(u.ID, id); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "token not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
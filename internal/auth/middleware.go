@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"cms/internal/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionCookie はセッションのトークンを入れる Cookie の名前
+const sessionCookie = "cms_session"
+
+// publicPaths はログインしなくても呼び出せる変更系の API
+var publicPaths = map[string]bool{
+	"/api/auth/login":  true,
+	"/api/auth/logout": true,
+}
+
+// Authenticate は Authorization: Bearer の API トークンか、セッションの Cookie からユーザーを特定する。
+// 変更する API（GET・HEAD・OPTIONS 以外）はログインしていなければ 401 を返す
+func (h *Handler) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		u, err := h.authenticate(c)
+		if err != nil {
+			if errors.Is(err, ErrUnauthorized) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if u != nil {
+			user.SetContext(c, u)
+			c.Next()
+			return
+		}
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if !publicPaths[c.FullPath()] {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": ErrUnauthorized.Error()})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// authenticate はリクエストの認証情報からユーザーを返す。
+// 認証情報がなければ nil、あっても無効なら ErrUnauthorized
+func (h *Handler) authenticate(c *gin.Context) (*user.User, error) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			return nil, ErrUnauthorized
+		}
+		return h.service.TokenUser(strings.TrimSpace(token))
+	}
+
+	token, err := c.Cookie(sessionCookie)
+	if err != nil || token == "" {
+		return nil, nil
+	}
+	u, err := h.service.SessionUser(token)
+	if errors.Is(err, ErrUnauthorized) {
+		// 期限切れなどの Cookie は消して、ログインしていないものとして扱う
+		setSessionCookie(c, "", -1)
+		return nil, nil
+	}
+	return u, err
+}
+
+// CurrentUser はログイン中のユーザーを返す
+func CurrentUser(c *gin.Context) (*user.User, bool) {
+	return user.FromContext(c)
+}
+
+// RequireUser はログイン中のユーザーを返す。ログインしていなければ 401 を返して false
+func RequireUser(c *gin.Context) (*user.User, bool) {
+	u, ok := CurrentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": ErrUnauthorized.Error()})
+		return nil, false
+	}
+	return u, true
+}
+
+// setSessionCookie はセッションの Cookie を設定する（maxAge が負なら削除）
+func setSessionCookie(c *gin.Context, token string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookie, token, maxAge, "/", "", c.Request.TLS != nil, true)
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuthenticateWithoutCredentials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use((&Handler{}).Authenticate())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/articles", ok)
+	r.POST("/api/articles", ok)
+	r.POST("/api/auth/login", ok)
+	r.POST("/api/auth/logout", ok)
+
+	tests := []struct {
+		method        string
+		path          string
+		authorization string
+		want          int
+	}{
+		{method: "GET", path: "/api/articles", want: http.StatusOK},
+		{method: "POST", path: "/api/articles", want: http.StatusUnauthorized},
+		{method: "POST", path: "/api/auth/login", want: http.StatusOK},
+		{method: "POST", path: "/api/auth/logout", want: http.StatusOK},
+		// Bearer 以外の Authorization は GET でも 401
+		{method: "GET", path: "/api/articles", authorization: "Token abc", want: http.StatusUnauthorized},
+		{method: "POST", path: "/api/auth/login", authorization: "Basic abc", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path+" "+tt.authorization, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
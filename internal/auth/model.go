@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrUnauthorized = errors.New("authentication required")
+
+// APIToken はスクリプト用の API トークン。トークン自体は作成したときにだけ返す
+type APIToken struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `json:"name"`
+	Token      string     `json:"token,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
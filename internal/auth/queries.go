@@ -0,0 +1,27 @@
+package auth
+
+import "embed"
+
+//go:embed queries/*.sql
+var queryFS embed.FS
+
+func loadQuery(name string) string {
+	data, err := queryFS.ReadFile("queries/" + name)
+	if err != nil {
+		panic("failed to load query: " + name)
+	}
+	return string(data)
+}
+
+var (
+	queryCreateSession         = loadQuery("create_session.sql")
+	queryGetSessionUser        = loadQuery("get_session_user.sql")
+	queryDeleteSession         = loadQuery("delete_session.sql")
+	queryDeleteExpiredSessions = loadQuery("delete_expired_sessions.sql")
+
+	queryCreateToken  = loadQuery("create_token.sql")
+	queryGetTokenUser = loadQuery("get_token_user.sql")
+	queryTouchToken   = loadQuery("touch_token.sql")
+	queryGetTokens    = loadQuery("get_tokens.sql")
+	queryDeleteToken  = loadQuery("delete_token.sql")
+)
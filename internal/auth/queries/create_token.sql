@@ -0,0 +1,2 @@
+INSERT INTO api_tokens (user_id, name, token_hash, created_at)
+VALUES (?, ?, ?, ?)
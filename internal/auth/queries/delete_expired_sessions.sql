@@ -0,0 +1 @@
+DELETE FROM sessions WHERE julianday(expires_at) <= julianday(?)
@@ -0,0 +1,4 @@
+SELECT u.id, u.email, u.password_hash, u.name, u.role, u.created_at, u.updated_at
+FROM sessions s
+INNER JOIN users u ON u.id = s.user_id
+WHERE s.token_hash = ? AND julianday(s.expires_at) > julianday(?)
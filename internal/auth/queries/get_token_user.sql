@@ -0,0 +1,4 @@
+SELECT t.id, u.id, u.email, u.password_hash, u.name, u.role, u.created_at, u.updated_at
+FROM api_tokens t
+INNER JOIN users u ON u.id = t.user_id
+WHERE t.token_hash = ?
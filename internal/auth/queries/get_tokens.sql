@@ -0,0 +1,4 @@
+SELECT id, user_id, name, created_at, last_used_at
+FROM api_tokens
+WHERE user_id = ?
+ORDER BY id DESC
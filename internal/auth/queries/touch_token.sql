@@ -0,0 +1 @@
+UPDATE api_tokens SET last_used_at = ? WHERE id = ?
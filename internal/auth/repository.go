@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"database/sql"
+	"time"
+
+	"cms/internal/user"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateSession(tokenHash string, userID int64, expiresAt time.Time) error {
+	_, err := r.db.Exec(queryCreateSession, tokenHash, userID, time.Now(), expiresAt)
+	return err
+}
+
+// SessionUser は期限内のセッションのユーザーを返す（なければ sql.ErrNoRows）
+func (r *Repository) SessionUser(tokenHash string, now time.Time) (*user.User, error) {
+	var u user.User
+	err := r.db.QueryRow(queryGetSessionUser, tokenHash, now).Scan(
+		&u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.Role, &u.CreatedAt, &u.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *Repository) DeleteSession(tokenHash string) error {
+	_, err := r.db.Exec(queryDeleteSession, tokenHash)
+	return err
+}
+
+func (r *Repository) DeleteExpiredSessions(now time.Time) error {
+	_, err := r.db.Exec(queryDeleteExpiredSessions, now)
+	return err
+}
+
+func (r *Repository) CreateToken(userID int64, name, tokenHash string) (*APIToken, error) {
+	now := time.Now()
+	result, err := r.db.Exec(queryCreateToken, userID, name, tokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &APIToken{ID: id, UserID: userID, Name: name, CreatedAt: now}, nil
+}
+
+// TokenUser は API トークンの ID とユーザーを返す（なければ sql.ErrNoRows）
+func (r *Repository) TokenUser(tokenHash string) (int64, *user.User, error) {
+	var tokenID int64
+	var u user.User
+	err := r.db.QueryRow(queryGetTokenUser, tokenHash).Scan(
+		&tokenID, &u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.Role, &u.CreatedAt, &u.UpdatedAt,
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+	return tokenID, &u, nil
+}
+
+// TouchToken は API トークンを最後に使った日時を記録する
+func (r *Repository) TouchToken(id int64, now time.Time) error {
+	_, err := r.db.Exec(queryTouchToken, now, id)
+	return err
+}
+
+func (r *Repository) GetTokens(userID int64) ([]APIToken, error) {
+	rows, err := r.db.Query(queryGetTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []APIToken{}
+	for rows.Next() {
+		var t APIToken
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.CreatedAt, &t.LastUsedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+// DeleteToken はユーザーの API トークンを削除する（なければ sql.ErrNoRows）
+func (r *Repository) DeleteToken(id, userID int64) error {
+	result, err := r.db.Exec(queryDeleteToken, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"cms/internal/user"
+)
+
+// セッションの有効期間
+const sessionTTL = 14 * 24 * time.Hour
+
+// tokenPrefix は API トークンの先頭に付ける文字列（設定ファイルなどに紛れても見分けられるように）
+const tokenPrefix = "cms_"
+
+type Service struct {
+	repo        *Repository
+	userService *user.Service
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{repo: NewRepository(db), userService: user.NewService(db)}
+}
+
+// Login はメールアドレスとパスワードを確認してセッションを作り、Cookie に入れるトークンを返す
+func (s *Service) Login(email, password string) (string, *user.User, time.Time, error) {
+	u, err := s.userService.Authenticate(email, password)
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+
+	now := time.Now()
+	// ついでに期限切れのセッションを掃除する
+	if err := s.repo.DeleteExpiredSessions(now); err != nil {
+		return "", nil, time.Time{}, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+	expiresAt := now.Add(sessionTTL)
+	if err := s.repo.CreateSession(hashToken(token), u.ID, expiresAt); err != nil {
+		return "", nil, time.Time{}, err
+	}
+	return token, u, expiresAt, nil
+}
+
+func (s *Service) Logout(token string) error {
+	return s.repo.DeleteSession(hashToken(token))
+}
+
+// SessionUser はセッションのトークンからユーザーを返す（無効なら ErrUnauthorized）
+func (s *Service) SessionUser(token string) (*user.User, error) {
+	u, err := s.repo.SessionUser(hashToken(token), time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUnauthorized
+	}
+	return u, err
+}
+
+// TokenUser は API トークンからユーザーを返す（無効なら ErrUnauthorized）
+func (s *Service) TokenUser(token string) (*user.User, error) {
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return nil, ErrUnauthorized
+	}
+	id, u, err := s.repo.TokenUser(hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.TouchToken(id, time.Now()); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// CreateToken は API トークンを発行する。トークンは保存しないので返り値でしか得られない
+func (s *Service) CreateToken(userID int64, name string) (*APIToken, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	token = tokenPrefix + token
+
+	t, err := s.repo.CreateToken(userID, strings.TrimSpace(name), hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	t.Token = token
+	return t, nil
+}
+
+func (s *Service) GetTokens(userID int64) ([]APIToken, error) {
+	return s.repo.GetTokens(userID)
+}
+
+// RevokeToken はユーザーの API トークンを失効させる（なければ sql.ErrNoRows）
+func (s *Service) RevokeToken(userID, id int64) error {
+	return s.repo.DeleteToken(id, userID)
+}
+
+// newToken はランダムなトークンを作る
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken は DB に保存するトークンのハッシュ
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -19,4 +19,11 @@ var (
 	queryCreate  = loadQuery("create.sql")
 	queryUpdate  = loadQuery("update.sql")
 	queryDelete  = loadQuery("delete.sql")
+
+	querySlugInTrash = loadQuery("slug_in_trash.sql")
+
+	queryGetTrashed           = loadQuery("get_trashed.sql")
+	queryRestore              = loadQuery("restore.sql")
+	queryPurge                = loadQuery("purge.sql")
+	queryClearArticleCategory = loadQuery("clear_article_category.sql")
 )
@@ -0,0 +1,3 @@
+UPDATE articles 
+SET category_id = NULL 
+WHERE category_id = ?
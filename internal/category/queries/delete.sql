@@ -1,2 +1,3 @@
-DELETE FROM categories WHERE id = ?
-
+UPDATE categories 
+SET deleted_at = ? 
+WHERE id = ? AND deleted_at IS NULL
@@ -1,4 +1,4 @@
-SELECT id, name, slug, created_at 
+SELECT id, name, slug, created_at, version 
 FROM categories 
+WHERE deleted_at IS NULL
 ORDER BY id DESC
-
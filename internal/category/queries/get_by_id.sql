@@ -1,4 +1,3 @@
-SELECT id, name, slug, created_at 
+SELECT id, name, slug, created_at, version 
 FROM categories 
-WHERE id = ?
-
+WHERE id = ? AND deleted_at IS NULL
@@ -0,0 +1,4 @@
+SELECT id, name, slug, created_at, deleted_at 
+FROM categories 
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
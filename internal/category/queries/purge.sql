@@ -0,0 +1 @@
+DELETE FROM categories WHERE id = ? AND deleted_at IS NOT NULL
@@ -0,0 +1,3 @@
+UPDATE categories 
+SET deleted_at = NULL 
+WHERE id = ? AND deleted_at IS NOT NULL
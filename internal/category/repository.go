@@ -2,7 +2,10 @@ package category
 
 import (
 	"database/sql"
+	"errors"
 	"time"
+
+	"github.com/mattn/go-sqlite3"
 )
 
 type Repository struct {
@@ -23,7 +26,7 @@ func (r *Repository) GetAll() ([]Category, error) {
 	var categories []Category
 	for rows.Next() {
 		var c Category
-		if err := rows.Scan(&c.ID, &c.Name, &c.Slug, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.Slug, &c.CreatedAt, &c.Version); err != nil {
 			return nil, err
 		}
 		categories = append(categories, c)
@@ -33,7 +36,7 @@ func (r *Repository) GetAll() ([]Category, error) {
 
 func (r *Repository) GetByID(id int64) (*Category, error) {
 	var c Category
-	err := r.db.QueryRow(queryGetByID, id).Scan(&c.ID, &c.Name, &c.Slug, &c.CreatedAt)
+	err := r.db.QueryRow(queryGetByID, id).Scan(&c.ID, &c.Name, &c.Slug, &c.CreatedAt, &c.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +46,7 @@ func (r *Repository) GetByID(id int64) (*Category, error) {
 func (r *Repository) Create(name, slug string) (*Category, error) {
 	result, err := r.db.Exec(queryCreate, name, slug, time.Now())
 	if err != nil {
-		return nil, err
+		return nil, r.uniqueError(err, slug)
 	}
 
 	id, err := result.LastInsertId()
@@ -54,16 +57,84 @@ func (r *Repository) Create(name, slug string) (*Category, error) {
 	return r.GetByID(id)
 }
 
-func (r *Repository) Update(id int64, name, slug string) (*Category, error) {
-	_, err := r.db.Exec(queryUpdate, name, slug, id)
+// Update は更新する。version が 0 でなければそのバージョンの場合だけ更新する
+// （対象の行がなければ sql.ErrNoRows）
+func (r *Repository) Update(id int64, name, slug string, version int64) (*Category, error) {
+	err := execAffected(r.db.Exec(queryUpdate, name, slug, id, version, version))
 	if err != nil {
-		return nil, err
+		return nil, r.uniqueError(err, slug)
 	}
 
 	return r.GetByID(id)
 }
 
+// Delete はゴミ箱に入れる（見つからなければ sql.ErrNoRows）
 func (r *Repository) Delete(id int64) error {
-	_, err := r.db.Exec(queryDelete, id)
+	return execAffected(r.db.Exec(queryDelete, time.Now(), id))
+}
+
+// GetTrashed はゴミ箱のカテゴリを新しく削除した順に返す
+func (r *Repository) GetTrashed() ([]Category, error) {
+	rows, err := r.db.Query(queryGetTrashed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []Category{}
+	for rows.Next() {
+		var c Category
+		if err := rows.Scan(&c.ID, &c.Name, &c.Slug, &c.CreatedAt, &c.DeletedAt); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+// Restore はゴミ箱から戻す
+func (r *Repository) Restore(id int64) error {
+	return execAffected(r.db.Exec(queryRestore, id))
+}
+
+// Purge はゴミ箱から完全に削除する
+func (r *Repository) Purge(id int64) error {
+	if err := execAffected(r.db.Exec(queryPurge, id)); err != nil {
+		return err
+	}
+	// 記事のカテゴリを外す（SQLite は外部キー制約が無効なため明示的に行う）
+	_, err := r.db.Exec(queryClearArticleCategory, id)
 	return err
 }
+
+// uniqueError は slug の UNIQUE 制約違反を ErrSlugTaken にする
+// （ゴミ箱の行が使っている場合は、戻すか完全に削除すれば使えるので ErrSlugInTrash）
+func (r *Repository) uniqueError(err error, slug string) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.ExtendedCode != sqlite3.ErrConstraintUnique {
+		return err
+	}
+	var trashed bool
+	if err := r.db.QueryRow(querySlugInTrash, slug).Scan(&trashed); err != nil {
+		return err
+	}
+	if trashed {
+		return ErrSlugInTrash
+	}
+	return ErrSlugTaken
+}
+
+// execAffected は対象の行がなければ sql.ErrNoRows を返す
+func execAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -1,6 +1,11 @@
 package category
 
-import "database/sql"
+import (
+	"database/sql"
+
+	"cms/internal/etag"
+	"cms/internal/user"
+)
 
 type Service struct {
 	repo *Repository
@@ -18,14 +23,59 @@ func (s *Service) GetByID(id int64) (*Category, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *Service) Create(name, slug string) (*Category, error) {
+// Create は作成する。編集者以上のみ
+func (s *Service) Create(actor *user.User, name, slug string) (*Category, error) {
+	if err := user.RequireEditor(actor); err != nil {
+		return nil, err
+	}
 	return s.repo.Create(name, slug)
 }
 
-func (s *Service) Update(id int64, name, slug string) (*Category, error) {
-	return s.repo.Update(id, name, slug)
+// Update は更新する（編集者以上のみ）。version が 0 でなければ、そのバージョンのときだけ更新する（違えば etag.ErrMismatch）
+func (s *Service) Update(actor *user.User, id int64, name, slug string, version int64) (*Category, error) {
+	if err := user.RequireEditor(actor); err != nil {
+		return nil, err
+	}
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && current.Version != version {
+		return nil, etag.ErrMismatch
+	}
+
+	category, err := s.repo.Update(id, name, slug, version)
+	if err == sql.ErrNoRows && version != 0 {
+		// 取得してから更新するまでの間に他で更新された
+		return nil, etag.ErrMismatch
+	}
+	return category, err
 }
 
-func (s *Service) Delete(id int64) error {
+// Delete はゴミ箱に入れる。編集者以上のみ
+func (s *Service) Delete(actor *user.User, id int64) error {
+	if err := user.RequireEditor(actor); err != nil {
+		return err
+	}
 	return s.repo.Delete(id)
 }
+
+func (s *Service) GetTrashed() ([]Category, error) {
+	return s.repo.GetTrashed()
+}
+
+// Restore はゴミ箱から戻す。編集者以上のみ
+func (s *Service) Restore(actor *user.User, id int64) error {
+	if err := user.RequireEditor(actor); err != nil {
+		return err
+	}
+	return s.repo.Restore(id)
+}
+
+// Purge は完全に削除する。編集者以上のみ
+func (s *Service) Purge(actor *user.User, id int64) error {
+	if err := user.RequireEditor(actor); err != nil {
+		return err
+	}
+	return s.repo.Purge(id)
+}
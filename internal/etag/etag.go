@@ -0,0 +1,74 @@
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrMismatch は If-Match の ETag が現在の内容と一致しない（他で更新された）
+var ErrMismatch = errors.New("resource has been modified")
+
+// Any は If-Match: * （現在の内容に関係なく更新する）
+const Any = "*"
+
+// Version はバージョンから ETag を作る
+func Version(v int64) string {
+	return `"` + strconv.FormatInt(v, 10) + `"`
+}
+
+// Hash は内容のハッシュから ETag を作る（バージョンを持たない設定用）
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// Set は ETag ヘッダを設定する
+func Set(c *gin.Context, tag string) {
+	c.Header("ETag", tag)
+}
+
+// IfMatch は If-Match ヘッダの ETag を返す。
+// ヘッダがなければ 428 を返して false（更新には If-Match が必須）。
+// If-Match は強い比較のため、弱い ETag（W/"..."）はそのまま返し、どの ETag とも一致しない
+func IfMatch(c *gin.Context) (string, bool) {
+	tag := strings.TrimSpace(c.GetHeader("If-Match"))
+	if tag == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header is required"})
+		return "", false
+	}
+	return tag, true
+}
+
+// IfMatchVersion は If-Match ヘッダのバージョンを返す（* なら 0）。
+// バージョンとして読めない ETag はどのバージョンとも一致しない -1 にする
+func IfMatchVersion(c *gin.Context) (int64, bool) {
+	tag, ok := IfMatch(c)
+	if !ok {
+		return 0, false
+	}
+	if tag == Any {
+		return 0, true
+	}
+
+	v, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
+	if err != nil || v <= 0 || tag != Version(v) {
+		return -1, true
+	}
+	return v, true
+}
+
+// PreconditionFailed は 412 と現在の内容・ETag を返す
+func PreconditionFailed(c *gin.Context, tag string, current any) {
+	Set(c, tag)
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":   ErrMismatch.Error(),
+		"etag":    tag,
+		"current": current,
+	})
+}
@@ -0,0 +1,95 @@
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		wantVersion int64
+		wantOK      bool
+	}{
+		{name: "missing", header: "", wantOK: false},
+		{name: "blank", header: "   ", wantOK: false},
+		{name: "any", header: "*", wantVersion: 0, wantOK: true},
+		{name: "version", header: `"3"`, wantVersion: 3, wantOK: true},
+		{name: "weak", header: `W/"3"`, wantVersion: -1, wantOK: true}, // If-Match は強い比較
+		{name: "weak any", header: `W/*`, wantVersion: -1, wantOK: true},
+		{name: "unquoted", header: "3", wantVersion: -1, wantOK: true},
+		{name: "zero", header: `"0"`, wantVersion: -1, wantOK: true},
+		{name: "negative", header: `"-1"`, wantVersion: -1, wantOK: true},
+		{name: "leading zero", header: `"03"`, wantVersion: -1, wantOK: true},
+		{name: "hash", header: `"a1b2c3"`, wantVersion: -1, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("PUT", "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			version, ok := IfMatchVersion(c)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				// If-Match がなければ 428 を返す
+				if w.Code != http.StatusPreconditionRequired {
+					t.Errorf("status = %d, want %d", w.Code, http.StatusPreconditionRequired)
+				}
+				return
+			}
+			if version != tt.wantVersion {
+				t.Errorf("version = %d, want %d", version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: `"a1b2"`, want: `"a1b2"`},
+		{header: ` "a1b2" `, want: `"a1b2"`},
+		{header: `W/"a1b2"`, want: `W/"a1b2"`}, // 弱い ETag は強い ETag と一致させない
+		{header: "*", want: "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("PUT", "/", nil)
+			c.Request.Header.Set("If-Match", tt.header)
+
+			tag, ok := IfMatch(c)
+			if !ok || tag != tt.want {
+				t.Errorf("IfMatch = %q, %v, want %q, true", tag, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreconditionFailed(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	PreconditionFailed(c, Version(4), map[string]int{"version": 4})
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusPreconditionFailed)
+	}
+	if got := w.Header().Get("ETag"); got != `"4"` {
+		t.Errorf("ETag = %q, want %q", got, `"4"`)
+	}
+}
@@ -0,0 +1,114 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"cms/internal/article"
+)
+
+// ArchiveYear は年別アーカイブの集計（URL はサイトルートからのパス）
+type ArchiveYear struct {
+	Year   int            `json:"year"`
+	Count  int            `json:"count"`
+	URL    string         `json:"url"`
+	Months []ArchiveMonth `json:"months"`
+
+	articles []article.Article
+}
+
+// ArchiveMonth は月別アーカイブの集計
+type ArchiveMonth struct {
+	Year  int    `json:"year"`
+	Month int    `json:"month"`
+	Count int    `json:"count"`
+	URL   string `json:"url"`
+
+	articles []article.Article
+}
+
+func archiveYearPath(year int) string {
+	return fmt.Sprintf("archive/%d/index.html", year)
+}
+
+func archiveMonthPath(year int, month time.Month) string {
+	return fmt.Sprintf("archive/%d/%02d.html", year, int(month))
+}
+
+// groupArchive は公開日時の降順に並んだ記事を年・月ごとにまとめる
+func groupArchive(articles []article.Article) []ArchiveYear {
+	var years []ArchiveYear
+	for _, a := range articles {
+		t := publishedAt(a)
+		year, month := t.Year(), t.Month()
+
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, ArchiveYear{Year: year, URL: archiveYearPath(year)})
+		}
+		y := &years[len(years)-1]
+		y.Count++
+		y.articles = append(y.articles, a)
+
+		if len(y.Months) == 0 || y.Months[len(y.Months)-1].Month != int(month) {
+			y.Months = append(y.Months, ArchiveMonth{Year: year, Month: int(month), URL: archiveMonthPath(year, month)})
+		}
+		m := &y.Months[len(y.Months)-1]
+		m.Count++
+		m.articles = append(m.articles, a)
+	}
+	return years
+}
+
+// exportArchive はアーカイブ（全体・年別・月別）のページを描画するジョブを作成
+func (s *Service) exportArchive(b *build, st *site) []func() error {
+	// アーカイブのトップ（年・月ごとの件数）
+	jobs := []func() error{func() error {
+		return s.renderArchivePage(b, "archive/index.html", "アーカイブ", map[string]interface{}{
+			"Years": st.archive,
+		})
+	}}
+
+	for _, y := range st.archive {
+		// 年別（月ごとの件数とその年の記事）
+		jobs = append(jobs, func() error {
+			return s.renderArchivePage(b, y.URL, fmt.Sprintf("アーカイブ: %d年", y.Year), map[string]interface{}{
+				"Year":     y.Year,
+				"Months":   y.Months,
+				"Articles": y.articles,
+			})
+		})
+
+		// 月別
+		for _, m := range y.Months {
+			jobs = append(jobs, func() error {
+				return s.renderArchivePage(b, m.URL, fmt.Sprintf("アーカイブ: %d年%d月", m.Year, m.Month), map[string]interface{}{
+					"Year":     m.Year,
+					"Month":    m.Month,
+					"Articles": m.articles,
+				})
+			})
+		}
+	}
+
+	return jobs
+}
+
+func (s *Service) renderArchivePage(b *build, path, title string, data map[string]interface{}) error {
+	return b.renderPage(filepath.FromSlash(path), data, func() ([]byte, error) {
+		vars := map[string]interface{}{"Root": rootOf(path)}
+		for k, v := range data {
+			vars[k] = v
+		}
+
+		// アーカイブテンプレート
+		var archiveBuf bytes.Buffer
+		if err := b.t.ExecuteTemplate(&archiveBuf, "archive.html", vars); err != nil {
+			return nil, err
+		}
+
+		// ベーステンプレート
+		return s.renderBase(b, rootOf(path), title, archiveBuf.String())
+	})
+}
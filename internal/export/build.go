@@ -0,0 +1,259 @@
+package export
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/yuin/goldmark"
+)
+
+// manifestFile はエクスポート先に保存するビルドマニフェストのファイル名
+const manifestFile = ".cms-manifest.json"
+
+// manifestVersion は生成ロジックを変更したときに上げる（全ページを再生成させる）
+const manifestVersion = 1
+
+// Result はエクスポート結果の集計
+type Result struct {
+	Written int `json:"written"`
+	Skipped int `json:"skipped"`
+	Deleted int `json:"deleted"`
+}
+
+// manifest は出力ファイルごとの入力ハッシュ（記事・テンプレート・設定）を記録する
+type manifest struct {
+	Version  int               `json:"version"`
+	Files    map[string]string `json:"files"`
+	AssetDir string            `json:"asset_dir,omitempty"` // 画像を出力したトップレベルのディレクトリ
+}
+
+// build は1回のエクスポート実行の状態（ページ描画は並列に行われる）
+type build struct {
+	cfg      Config
+	dir      string // 出力先（ステージングディレクトリ）
+	t        *template.Template
+	md       goldmark.Markdown
+	siteHash string
+	prev     *manifest
+
+	mu     sync.Mutex // next と result を保護
+	next   *manifest
+	result Result
+
+	// only が設定されている場合は、そのファイルだけを描画してメモリに保持する（プレビュー用）
+	only     string
+	rendered []byte
+}
+
+func newBuild(cfg Config, dir string, t *template.Template, templateSources map[string]string) *build {
+	b := &build{
+		cfg:  cfg,
+		dir:  dir,
+		t:    t,
+		md:   newMarkdown(cfg),
+		prev: loadManifest(dir),
+		next: &manifest{Version: manifestVersion, Files: map[string]string{}, AssetDir: assetDir(cfg)},
+	}
+	// サイト全体に影響する入力（テンプレート内容と設定）
+	b.siteHash = hashOf(map[string]interface{}{
+		"version":   manifestVersion,
+		"templates": templateSources,
+		"config":    cfg,
+	})
+	return b
+}
+
+// renderPage は入力が前回から変わっていなければ描画自体をスキップし、
+// 変わっていれば描画して内容が異なる場合のみ書き込む
+func (b *build) renderPage(rel string, inputs interface{}, render func() ([]byte, error)) error {
+	if b.only != "" {
+		if filepath.ToSlash(rel) != b.only {
+			return nil
+		}
+		data, err := render()
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.ToSlash(rel), err)
+		}
+		b.rendered = data
+		return nil
+	}
+
+	hash := hashOf(map[string]interface{}{
+		"site":   b.siteHash,
+		"inputs": inputs,
+	})
+	b.mu.Lock()
+	b.next.Files[filepath.ToSlash(rel)] = hash
+	b.mu.Unlock()
+
+	path := filepath.Join(b.dir, rel)
+	if hash != "" && b.prev.Files[filepath.ToSlash(rel)] == hash {
+		if _, err := os.Stat(path); err == nil {
+			b.count(&b.result.Skipped)
+			return nil
+		}
+	}
+
+	data, err := render()
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.ToSlash(rel), err)
+	}
+	return b.writeFile(rel, data)
+}
+
+// writeFile は既存ファイルと内容が異なる場合のみ書き込む（mtime を無駄に更新しない）
+func (b *build) writeFile(rel string, data []byte) error {
+	if b.only != "" {
+		if filepath.ToSlash(rel) == b.only {
+			b.rendered = data
+		}
+		return nil
+	}
+
+	path := filepath.Join(b.dir, rel)
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		b.count(&b.result.Skipped)
+		return nil
+	}
+
+	// ステージングのファイルは公開中のファイルとハードリンクされている場合があるため、
+	// 上書きせずに一度削除してから書き込む
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// 一覧の2ページ目以降などは出力先ディレクトリが未作成の場合がある
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	b.count(&b.result.Written)
+	return nil
+}
+
+// removeFile は不要になった出力ファイルを削除
+func (b *build) removeFile(path string) error {
+	// マニフェストは実際に存在するファイルだけを記録する
+	if rel, err := filepath.Rel(b.dir, path); err == nil {
+		b.mu.Lock()
+		delete(b.next.Files, filepath.ToSlash(rel))
+		b.mu.Unlock()
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	b.count(&b.result.Deleted)
+	return nil
+}
+
+// generated は今回のビルドで描画したファイルかどうか
+func (b *build) generated(rel string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.next.Files[filepath.ToSlash(rel)]
+	return ok
+}
+
+func (b *build) count(n *int) {
+	b.mu.Lock()
+	*n++
+	b.mu.Unlock()
+}
+
+// runJobs はジョブを最大 workers 並列で実行し、発生したエラーをすべてまとめて返す
+func runJobs(workers int, jobs []func() error) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	sem := make(chan struct{}, workers)
+
+	for _, job := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := job(); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// verify はマニフェストに記録された全ページが出力されていることを確認
+func (b *build) verify() error {
+	var errs []error
+	for rel := range b.next.Files {
+		if _, err := os.Stat(filepath.Join(b.dir, filepath.FromSlash(rel))); err != nil {
+			errs = append(errs, fmt.Errorf("%s: page was not rendered", rel))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *build) saveManifest() error {
+	data, err := json.MarshalIndent(b.next, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// ステージングのマニフェストは公開中のものとハードリンクされているため、
+	// 上書きすると入れ替え前に公開中のマニフェストまで変わってしまう
+	path := filepath.Join(b.dir, manifestFile)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// loadManifest は前回のマニフェストを読み込む（なければ空、バージョン違いは破棄）
+func loadManifest(exportDir string) *manifest {
+	empty := &manifest{Version: manifestVersion, Files: map[string]string{}}
+
+	data, err := os.ReadFile(filepath.Join(exportDir, manifestFile))
+	if err != nil {
+		return empty
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil || m.Version != manifestVersion || m.Files == nil {
+		return empty
+	}
+	return &m
+}
+
+func hashOf(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// JSON化できない入力は常に再生成させる
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
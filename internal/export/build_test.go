@@ -0,0 +1,228 @@
+package export
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRenderPageManifest(t *testing.T) {
+	tests := []struct {
+		name        string
+		inputs      string
+		content     string
+		removeFile  bool
+		wantRender  bool
+		wantWritten int
+		wantSkipped int
+	}{
+		{name: "unchanged inputs skip rendering", inputs: "v1", content: "a", wantRender: false, wantSkipped: 1},
+		{name: "changed inputs with same output skip writing", inputs: "v2", content: "a", wantRender: true, wantSkipped: 1},
+		{name: "changed inputs with new output are written", inputs: "v2", content: "b", wantRender: true, wantWritten: 1},
+		{name: "missing file is rendered again", inputs: "v1", content: "a", removeFile: true, wantRender: true, wantWritten: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			// 1回目のビルドでページとマニフェストを書き出す
+			first := newBuild(Config{}, dir, nil, nil)
+			if err := first.renderPage("index.html", "v1", func() ([]byte, error) { return []byte("a"), nil }); err != nil {
+				t.Fatal(err)
+			}
+			if err := first.saveManifest(); err != nil {
+				t.Fatal(err)
+			}
+			if first.result.Written != 1 {
+				t.Fatalf("first build written = %d, want 1", first.result.Written)
+			}
+			if tt.removeFile {
+				if err := os.Remove(filepath.Join(dir, "index.html")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			b := newBuild(Config{}, dir, nil, nil)
+			rendered := false
+			err := b.renderPage("index.html", tt.inputs, func() ([]byte, error) {
+				rendered = true
+				return []byte(tt.content), nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if rendered != tt.wantRender {
+				t.Errorf("rendered = %v, want %v", rendered, tt.wantRender)
+			}
+			if b.result.Written != tt.wantWritten || b.result.Skipped != tt.wantSkipped {
+				t.Errorf("written, skipped = %d, %d, want %d, %d",
+					b.result.Written, b.result.Skipped, tt.wantWritten, tt.wantSkipped)
+			}
+			if !b.generated("index.html") {
+				t.Error("index.html is not recorded in the manifest")
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "index.html"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.content {
+				t.Errorf("content = %q, want %q", data, tt.content)
+			}
+		})
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantFiles int
+	}{
+		{name: "valid", data: `{"version": 1, "files": {"index.html": "abc"}}`, wantFiles: 1},
+		{name: "other version", data: `{"version": 0, "files": {"index.html": "abc"}}`, wantFiles: 0},
+		{name: "no files", data: `{"version": 1}`, wantFiles: 0},
+		{name: "broken", data: `{`, wantFiles: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, manifestFile), []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			m := loadManifest(dir)
+			if m.Version != manifestVersion {
+				t.Errorf("version = %d, want %d", m.Version, manifestVersion)
+			}
+			if len(m.Files) != tt.wantFiles {
+				t.Errorf("files = %d, want %d", len(m.Files), tt.wantFiles)
+			}
+		})
+	}
+}
+
+func TestRemoveFileUpdatesManifest(t *testing.T) {
+	dir := t.TempDir()
+	b := newBuild(Config{}, dir, nil, nil)
+	if err := b.renderPage("posts/a.html", "a", func() ([]byte, error) { return []byte("a"), nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "posts", "a.html")
+	if err := b.removeFile(path); err != nil {
+		t.Fatal(err)
+	}
+	// 存在しないファイルの削除はエラーにも削除数にもならない
+	if err := b.removeFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.generated("posts/a.html") {
+		t.Error("removed file is still recorded in the manifest")
+	}
+	if b.result.Deleted != 1 {
+		t.Errorf("deleted = %d, want 1", b.result.Deleted)
+	}
+}
+
+func TestRunJobs(t *testing.T) {
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+
+	tests := []struct {
+		name     string
+		workers  int
+		results  []error
+		wantErrs []error
+	}{
+		{name: "no jobs", workers: 2},
+		{name: "all succeed", workers: 2, results: []error{nil, nil, nil}},
+		{name: "one fails", workers: 2, results: []error{nil, errA, nil}, wantErrs: []error{errA}},
+		{name: "all errors are collected", workers: 1, results: []error{errA, nil, errB}, wantErrs: []error{errA, errB}},
+		{name: "default workers", workers: 0, results: []error{errA, errB}, wantErrs: []error{errA, errB}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran, running, maxRunning atomic.Int32
+			var jobs []func() error
+			for _, result := range tt.results {
+				jobs = append(jobs, func() error {
+					n := running.Add(1)
+					defer running.Add(-1)
+					for {
+						m := maxRunning.Load()
+						if n <= m || maxRunning.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					ran.Add(1)
+					return result
+				})
+			}
+
+			err := runJobs(tt.workers, jobs)
+
+			// 失敗したジョブがあっても残りのジョブはすべて実行する
+			if int(ran.Load()) != len(tt.results) {
+				t.Errorf("ran %d jobs, want %d", ran.Load(), len(tt.results))
+			}
+			if tt.workers > 0 && int(maxRunning.Load()) > tt.workers {
+				t.Errorf("%d jobs ran at once, want at most %d", maxRunning.Load(), tt.workers)
+			}
+			if len(tt.wantErrs) == 0 && err != nil {
+				t.Errorf("err = %v, want nil", err)
+			}
+			for _, want := range tt.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("err = %v, want it to contain %v", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveManifestKeepsLiveManifest(t *testing.T) {
+	exportDir := t.TempDir()
+	live := newBuild(Config{}, exportDir, nil, nil)
+	if err := live.renderPage("index.html", "v1", func() ([]byte, error) { return []byte("a"), nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := live.saveManifest(); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(filepath.Join(exportDir, manifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ステージングには公開中のマニフェストがハードリンクで複製される
+	staging, err := prepareStaging(exportDir, []string{manifestFile, "index.html"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := newBuild(Config{}, staging, nil, nil)
+	if err := b.renderPage("index.html", "v2", func() ([]byte, error) { return []byte("b"), nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.saveManifest(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 入れ替える前に失敗しても、公開中のマニフェストは変わらない
+	after, err := os.ReadFile(filepath.Join(exportDir, manifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("live manifest was changed before the swap:\n%s", after)
+	}
+	if m := loadManifest(staging); m.Files["index.html"] == loadManifest(exportDir).Files["index.html"] {
+		t.Error("staging manifest does not record the new inputs")
+	}
+}
@@ -0,0 +1,267 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cms/internal/article"
+	"cms/internal/category"
+	"cms/internal/tag"
+)
+
+// フィードに含める記事の最大件数
+const feedLimit = 20
+
+// Atom 1.0
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// RSS 2.0
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	XmlnsAtom string     `xml:"xmlns:atom,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// exportFeeds はサイト全体（Atom / RSS）とカテゴリ別・タグ別（Atom）のフィードを生成
+func (s *Service) exportFeeds(b *build, st *site) error {
+	cfg := b.cfg
+	articles := st.articles
+
+	// フィードは絶対URLが必須のため、ベースURL未設定時は生成しない
+	if cfg.BaseURL == "" {
+		return nil
+	}
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+
+	// 記事本文のHTMLを一度だけ生成（画像は絶対URLに変換）
+	contents := make(map[int64]string, len(articles))
+	for _, a := range articles {
+		html, _, err := b.renderContent(a.Content, baseURL+"/"+assetPath(cfg)+"/")
+		if err != nil {
+			return err
+		}
+		contents[a.ID] = html
+	}
+
+	// サイト全体
+	if err := writeAtomFeed(b,
+		"feed.xml",
+		cfg.SiteTitle, baseURL+"/index.html", baseURL+"/feed.xml",
+		baseURL, articles, contents,
+	); err != nil {
+		return err
+	}
+	if err := writeRSSFeed(b, baseURL, articles, contents); err != nil {
+		return err
+	}
+
+	// カテゴリ別
+	for _, p := range st.categories {
+		c := p.Category
+		if err := writeAtomFeed(b,
+			filepath.Join("categories", c.Slug+".xml"),
+			cfg.SiteTitle+" - カテゴリ: "+c.Name,
+			categoryURL(baseURL, c), baseURL+"/categories/"+url.PathEscape(c.Slug)+".xml",
+			baseURL, p.Articles, contents,
+		); err != nil {
+			return err
+		}
+	}
+
+	// タグ別
+	for _, p := range st.tags {
+		tg := p.Tag
+		if err := writeAtomFeed(b,
+			filepath.Join("tags", tg.Slug+".xml"),
+			cfg.SiteTitle+" - タグ: "+tg.Name,
+			tagURL(baseURL, tg), baseURL+"/tags/"+url.PathEscape(tg.Slug)+".xml",
+			baseURL, p.Articles, contents,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeAtomFeed(b *build, path, title, alternateURL, selfURL, baseURL string, articles []article.Article, contents map[int64]string) error {
+	articles = limitArticles(articles)
+
+	feed := atomFeed{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		Title:   title,
+		ID:      selfURL,
+		Updated: latestUpdate(articles).Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: alternateURL, Rel: "alternate", Type: "text/html"},
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+
+	for _, a := range articles {
+		link := postURL(baseURL, a)
+		entry := atomEntry{
+			Title:     a.Title,
+			ID:        link,
+			Link:      atomLink{Href: link, Rel: "alternate", Type: "text/html"},
+			Published: publishedAt(a).Format(time.RFC3339),
+			Updated:   a.UpdatedAt.Format(time.RFC3339),
+			Content:   atomContent{Type: "html", Body: contents[a.ID]},
+		}
+		for _, t := range a.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: t.Name})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return writeXML(b, path, feed)
+}
+
+func writeRSSFeed(b *build, baseURL string, articles []article.Article, contents map[int64]string) error {
+	articles = limitArticles(articles)
+
+	feed := rssFeed{
+		Version:   "2.0",
+		XmlnsAtom: "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         b.cfg.SiteTitle,
+			Link:          baseURL + "/index.html",
+			Description:   b.cfg.SiteTitle,
+			LastBuildDate: latestUpdate(articles).Format(time.RFC1123Z),
+			AtomLink:      atomLink{Href: baseURL + "/rss.xml", Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+
+	for _, a := range articles {
+		link := postURL(baseURL, a)
+		item := rssItem{
+			Title:       a.Title,
+			Link:        link,
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			PubDate:     publishedAt(a).Format(time.RFC1123Z),
+			Description: contents[a.ID],
+		}
+		for _, t := range a.Tags {
+			item.Categories = append(item.Categories, t.Name)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	return writeXML(b, "rss.xml", feed)
+}
+
+func writeXML(b *build, path string, v interface{}) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	buf.WriteString("\n")
+
+	return b.writeFile(path, buf.Bytes())
+}
+
+func postURL(baseURL string, a article.Article) string {
+	return baseURL + "/posts/" + url.PathEscape(a.Slug) + ".html"
+}
+
+func categoryURL(baseURL string, c category.Category) string {
+	return baseURL + "/categories/" + url.PathEscape(c.Slug) + ".html"
+}
+
+func tagURL(baseURL string, t tag.Tag) string {
+	return baseURL + "/tags/" + url.PathEscape(t.Slug) + ".html"
+}
+
+// publishedAt は公開日時（未設定の場合は作成日時）を返す
+func publishedAt(a article.Article) time.Time {
+	if a.PublishedAt != nil {
+		return *a.PublishedAt
+	}
+	return a.CreatedAt
+}
+
+// latestUpdate は記事の中で最も新しい更新日時を返す
+func latestUpdate(articles []article.Article) time.Time {
+	var latest time.Time
+	for _, a := range articles {
+		if a.UpdatedAt.After(latest) {
+			latest = a.UpdatedAt
+		}
+	}
+	if latest.IsZero() {
+		return time.Now()
+	}
+	return latest
+}
+
+func limitArticles(articles []article.Article) []article.Article {
+	if len(articles) > feedLimit {
+		return articles[:feedLimit]
+	}
+	return articles
+}
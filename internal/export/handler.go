@@ -3,8 +3,11 @@ package export
 import (
 	"database/sql"
 	"net/http"
+	"strconv"
 
+	"cms/internal/auth"
 	"cms/internal/settings"
+	"cms/internal/user"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,25 +26,160 @@ func NewHandler(db *sql.DB) *Handler {
 
 func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	r.POST("/export", h.Export)
+	r.GET("/articles/:id/preview", h.PreviewArticle)
+	r.POST("/preview", h.Preview)
+	r.GET("/preview/css/highlight.css", h.PreviewCSS)
+}
+
+// NewConfig は設定ファイルの内容からエクスポート・プレビューの設定を作る
+func NewConfig(s *settings.Settings) Config {
+	return Config{
+		ExportDir: s.ExportDir,
+		UploadDir: "./uploads",
+		SiteTitle: s.SiteTitle,
+		BaseURL:   s.BaseURL,
+		AssetPath: s.AssetPath,
+		RobotsTxt: s.RobotsTxt,
+		PageSize:  s.PageSize,
+
+		HighlightStyle:       s.HighlightStyle,
+		HighlightLineNumbers: s.HighlightLineNumbers,
+
+		MarkdownExtensions: s.MarkdownExtensions,
+	}
+}
+
+// config は設定ファイルからエクスポート・プレビューの設定を作る
+func (h *Handler) config() (Config, error) {
+	s, err := h.settingsService.Get()
+	if err != nil {
+		return Config{}, err
+	}
+	return NewConfig(s), nil
 }
 
 func (h *Handler) Export(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	// 設定からexport_dirを取得
-	s, err := h.settingsService.Get()
+	cfg, err := h.config()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load settings: " + err.Error()})
 		return
 	}
 
-	cfg := Config{
-		ExportDir: s.ExportDir,
-		UploadDir: "./uploads",
-		SiteTitle: s.SiteTitle,
+	result, err := h.service.Export(u, cfg)
+	if err != nil {
+		if err == user.ErrForbidden {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "export completed",
+		"export_dir": cfg.ExportDir,
+		"written":    result.Written,
+		"skipped":    result.Skipped,
+		"deleted":    result.Deleted,
+	})
+}
+
+// PreviewArticle は保存済みの記事（下書きを含む）をHTMLで返す
+func (h *Handler) PreviewArticle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	cfg, err := h.config()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load settings: " + err.Error()})
+		return
 	}
-	if err := h.service.Export(cfg); err != nil {
+
+	html, err := h.service.PreviewArticle(cfg, id, requestOrigin(c))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", html)
+}
+
+type PreviewRequest struct {
+	Title      string  `json:"title"`
+	Slug       string  `json:"slug"`
+	Content    string  `json:"content"`
+	CategoryID *int64  `json:"category_id"`
+	TagIDs     []int64 `json:"tag_ids"`
+}
+
+// Preview は保存前の Markdown をHTMLで返す
+func (h *Handler) Preview(c *gin.Context) {
+	var req PreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cfg, err := h.config()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load settings: " + err.Error()})
+		return
+	}
+
+	html, err := h.service.PreviewContent(cfg, PreviewInput{
+		Title:      req.Title,
+		Slug:       req.Slug,
+		Content:    req.Content,
+		CategoryID: req.CategoryID,
+		TagIDs:     req.TagIDs,
+	}, requestOrigin(c))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", html)
+}
+
+// requestOrigin はリクエストを受けた API サーバーのオリジンを返す
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// PreviewCSS はプレビューのHTMLが読み込むハイライト用のスタイルシートを返す
+func (h *Handler) PreviewCSS(c *gin.Context) {
+	cfg, err := h.config()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load settings: " + err.Error()})
+		return
+	}
+
+	css, err := h.service.PreviewCSS(cfg)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "export completed", "export_dir": s.ExportDir})
+	c.Data(http.StatusOK, "text/css; charset=utf-8", css)
 }
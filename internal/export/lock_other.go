@@ -0,0 +1,24 @@
+//go:build !unix
+
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lockExportDir はエクスポート先にロックファイルを作成する。
+// flock が使えないため、ロックファイルが既にあれば待たずにエラーにする
+func lockExportDir(exportDir string) (func(), error) {
+	path := filepath.Join(exportDir, lockFile)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if os.IsExist(err) {
+		return nil, fmt.Errorf("another export is running (remove %s if it is not)", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	return func() { os.Remove(path) }, nil
+}
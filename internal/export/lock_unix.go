@@ -0,0 +1,23 @@
+//go:build unix
+
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockExportDir はエクスポート先のロックファイルに排他ロックをかける（解放されるまで待つ）。
+// ロックはプロセスが終了すると OS が解放するため、異常終了してもロックが残らない
+func lockExportDir(exportDir string) (func(), error) {
+	f, err := os.OpenFile(filepath.Join(exportDir, lockFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() { f.Close() }, nil
+}
@@ -0,0 +1,42 @@
+//go:build unix
+
+package export
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockExportDir(t *testing.T) {
+	dir := t.TempDir()
+	unlock, err := lockExportDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 別のエクスポートはロックが解放されるまで待つ
+	acquired := make(chan struct{})
+	go func() {
+		unlock2, err := lockExportDir(dir)
+		if err != nil {
+			t.Error(err)
+			close(acquired)
+			return
+		}
+		unlock2()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("lock was acquired while another export held it")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock was not acquired after it was released")
+	}
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"bytes"
+	"path/filepath"
+	"sort"
+
+	"cms/internal/markdown"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// defaultHighlightStyle はコードブロックのハイライトに使う chroma のスタイルの既定値
+const defaultHighlightStyle = "github-dark"
+
+// highlightCSSFile はハイライト用スタイルシートの出力先
+const highlightCSSFile = "css/highlight.css"
+
+// newMarkdown は設定に合わせた Markdown 変換器を作成
+func newMarkdown(cfg Config) goldmark.Markdown {
+	extensions := []goldmark.Extender{
+		// 言語指定のないコードブロック・未対応の言語はハイライトせずにそのまま出力される
+		highlighting.NewHighlighting(
+			highlighting.WithStyle(highlightStyle(cfg)),
+			highlighting.WithFormatOptions(highlightOptions(cfg)...),
+		),
+	}
+	for _, name := range enabledExtensions(cfg) {
+		extensions = append(extensions, markdown.Extensions[name])
+	}
+
+	return goldmark.New(
+		goldmark.WithExtensions(extensions...),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithASTTransformers(util.Prioritized(&apiURLRewriter{}, 100)),
+		),
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+			renderer.WithNodeRenderers(util.Prioritized(&rawHTMLRenderer{}, 100)),
+		),
+	)
+}
+
+// enabledExtensions は有効な拡張の名前を返す（未設定ならすべて有効、未知の名前は無視）
+func enabledExtensions(cfg Config) []string {
+	if cfg.MarkdownExtensions == nil {
+		names := make([]string, 0, len(markdown.Extensions))
+		for name := range markdown.Extensions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	var names []string
+	for _, name := range cfg.MarkdownExtensions {
+		if markdown.ValidExtension(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// highlightStyle はハイライトのスタイル名を返す（空または未知の名前なら既定値）
+func highlightStyle(cfg Config) string {
+	if _, ok := styles.Registry[cfg.HighlightStyle]; ok {
+		return cfg.HighlightStyle
+	}
+	return defaultHighlightStyle
+}
+
+// highlightOptions はインラインスタイルではなくクラスで出力する（色はスタイルシートで指定）
+func highlightOptions(cfg Config) []chromahtml.Option {
+	return []chromahtml.Option{
+		chromahtml.WithClasses(true),
+		chromahtml.WithLineNumbers(cfg.HighlightLineNumbers),
+	}
+}
+
+// highlightCSS はハイライト用のスタイルシートを生成
+func highlightCSS(cfg Config) ([]byte, error) {
+	var buf bytes.Buffer
+	formatter := chromahtml.New(highlightOptions(cfg)...)
+	if err := formatter.WriteCSS(&buf, styles.Get(highlightStyle(cfg))); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportHighlightCSS はハイライト用のスタイルシートを出力
+func (s *Service) exportHighlightCSS(b *build) error {
+	css, err := highlightCSS(b.cfg)
+	if err != nil {
+		return err
+	}
+	return b.writeFile(filepath.FromSlash(highlightCSSFile), css)
+}
@@ -0,0 +1,194 @@
+package export
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cms/internal/article"
+)
+
+// Pagination は一覧ページのテンプレートに渡すページ送り情報（URLは現在のページからの相対パス）
+type Pagination struct {
+	Page       int        `json:"page"`
+	TotalPages int        `json:"total_pages"`
+	TotalItems int        `json:"total_items"`
+	PerPage    int        `json:"per_page"`
+	HasPrev    bool       `json:"has_prev"`
+	HasNext    bool       `json:"has_next"`
+	PrevURL    string     `json:"prev_url"`
+	NextURL    string     `json:"next_url"`
+	Pages      []PageLink `json:"pages"`
+}
+
+type PageLink struct {
+	Number  int    `json:"number"`
+	URL     string `json:"url"`
+	Current bool   `json:"current"`
+}
+
+// listing はページ分割される一覧（トップ・カテゴリ別・タグ別）
+type listing struct {
+	firstPath string // 1ページ目の出力パス（例: categories/tech.html）
+	pageDir   string // 2ページ目以降の親ディレクトリ（例: categories/tech）
+	articles  []article.Article
+}
+
+// pagePath は n ページ目の出力パスを返す（2ページ目以降は {pageDir}/page/{n}.html）
+func (l listing) pagePath(n int) string {
+	if n <= 1 {
+		return l.firstPath
+	}
+	return path.Join(l.pageDir, "page", strconv.Itoa(n)+".html")
+}
+
+// pages は記事を perPage 件ずつに分割（0以下なら分割しない、記事がなくても1ページは作る）
+func (l listing) pages(perPage int) [][]article.Article {
+	if perPage <= 0 || len(l.articles) <= perPage {
+		return [][]article.Article{l.articles}
+	}
+
+	var pages [][]article.Article
+	for start := 0; start < len(l.articles); start += perPage {
+		end := start + perPage
+		if end > len(l.articles) {
+			end = len(l.articles)
+		}
+		pages = append(pages, l.articles[start:end])
+	}
+	return pages
+}
+
+// pagination は n ページ目のページ送り情報を作成
+func (l listing) pagination(n, totalPages, perPage int) *Pagination {
+	current := l.pagePath(n)
+	p := &Pagination{
+		Page:       n,
+		TotalPages: totalPages,
+		TotalItems: len(l.articles),
+		PerPage:    perPage,
+		HasPrev:    n > 1,
+		HasNext:    n < totalPages,
+	}
+	if p.HasPrev {
+		p.PrevURL = relPath(current, l.pagePath(n-1))
+	}
+	if p.HasNext {
+		p.NextURL = relPath(current, l.pagePath(n+1))
+	}
+	for i := 1; i <= totalPages; i++ {
+		p.Pages = append(p.Pages, PageLink{
+			Number:  i,
+			URL:     relPath(current, l.pagePath(i)),
+			Current: i == n,
+		})
+	}
+	return p
+}
+
+// listingJobs は一覧の各ページを描画するジョブを作成
+func listingJobs(b *build, l listing, inputs map[string]interface{}, render func(path string, items []article.Article, p *Pagination) ([]byte, error)) []func() error {
+	pages := l.pages(b.cfg.PageSize)
+
+	var jobs []func() error
+	for i, items := range pages {
+		n := i + 1
+		pagePath := l.pagePath(n)
+		p := l.pagination(n, len(pages), b.cfg.PageSize)
+
+		pageInputs := map[string]interface{}{"Articles": items, "Pagination": p}
+		for k, v := range inputs {
+			pageInputs[k] = v
+		}
+
+		jobs = append(jobs, func() error {
+			return b.renderPage(filepath.FromSlash(pagePath), pageInputs, func() ([]byte, error) {
+				return render(pagePath, items, p)
+			})
+		})
+	}
+	return jobs
+}
+
+// rootOf はページからサイトのルートへの相対パスを返す（例: posts/a.html → ../）
+func rootOf(pagePath string) string {
+	return strings.Repeat("../", strings.Count(pagePath, "/"))
+}
+
+// relPath はページ from から見た to への相対パスを返す（どちらもサイトルートからのパス）
+func relPath(from, to string) string {
+	return rootOf(from) + to
+}
+
+// cleanupPagination は今回生成されなかったページ送りのファイルとディレクトリを削除
+func (s *Service) cleanupPagination(b *build) error {
+	// トップの page/
+	if err := s.cleanupUngenerated(b, "page"); err != nil {
+		return err
+	}
+
+	// categories/{slug}/ と tags/{slug}/
+	for _, subdir := range []string{"categories", "tags"} {
+		entries, err := os.ReadDir(filepath.Join(b.dir, subdir))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			if err := s.cleanupUngenerated(b, filepath.Join(subdir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// cleanupUngenerated は dir 以下のうち今回のビルドで生成していないファイルを削除し、空のディレクトリも削除する
+func (s *Service) cleanupUngenerated(b *build, dir string) error {
+	root := filepath.Join(b.dir, dir)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil
+	}
+
+	var dirs []string
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			dirs = append(dirs, p)
+			return nil
+		}
+
+		rel, err := filepath.Rel(b.dir, p)
+		if err != nil {
+			return err
+		}
+		if !b.generated(rel) {
+			return b.removeFile(p)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 深い階層から順に、空になったディレクトリを削除
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if entries, err := os.ReadDir(dirs[i]); err == nil && len(entries) == 0 {
+			if err := os.Remove(dirs[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cms/internal/article"
+)
+
+func TestListingPages(t *testing.T) {
+	tests := []struct {
+		name     string
+		articles int
+		perPage  int
+		want     []int // ページごとの記事数
+	}{
+		{name: "no articles", articles: 0, perPage: 2, want: []int{0}},
+		{name: "not paginated", articles: 5, perPage: 0, want: []int{5}},
+		{name: "fits in one page", articles: 2, perPage: 2, want: []int{2}},
+		{name: "last page is short", articles: 5, perPage: 2, want: []int{2, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := listing{firstPath: "index.html", articles: make([]article.Article, tt.articles)}
+			pages := l.pages(tt.perPage)
+			if len(pages) != len(tt.want) {
+				t.Fatalf("pages = %d, want %d", len(pages), len(tt.want))
+			}
+			for i, items := range pages {
+				if len(items) != tt.want[i] {
+					t.Errorf("page %d has %d articles, want %d", i+1, len(items), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestListingPagination(t *testing.T) {
+	l := listing{firstPath: "categories/go.html", pageDir: "categories/go", articles: make([]article.Article, 5)}
+
+	tests := []struct {
+		page     int
+		wantPath string
+		wantPrev string
+		wantNext string
+	}{
+		{page: 1, wantPath: "categories/go.html", wantNext: "../categories/go/page/2.html"},
+		{page: 2, wantPath: "categories/go/page/2.html", wantPrev: "../../../categories/go.html", wantNext: "../../../categories/go/page/3.html"},
+		{page: 3, wantPath: "categories/go/page/3.html", wantPrev: "../../../categories/go/page/2.html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantPath, func(t *testing.T) {
+			if got := l.pagePath(tt.page); got != tt.wantPath {
+				t.Errorf("pagePath = %q, want %q", got, tt.wantPath)
+			}
+
+			p := l.pagination(tt.page, 3, 2)
+			if p.HasPrev != (tt.wantPrev != "") || p.PrevURL != tt.wantPrev {
+				t.Errorf("prev = %v %q, want %q", p.HasPrev, p.PrevURL, tt.wantPrev)
+			}
+			if p.HasNext != (tt.wantNext != "") || p.NextURL != tt.wantNext {
+				t.Errorf("next = %v %q, want %q", p.HasNext, p.NextURL, tt.wantNext)
+			}
+			if p.TotalItems != 5 || len(p.Pages) != 3 {
+				t.Errorf("total items, pages = %d, %d, want 5, 3", p.TotalItems, len(p.Pages))
+			}
+			for _, link := range p.Pages {
+				if link.Current != (link.Number == tt.page) {
+					t.Errorf("page %d current = %v", link.Number, link.Current)
+				}
+			}
+		})
+	}
+}
+
+func TestCleanupPagination(t *testing.T) {
+	dir := t.TempDir()
+	b := newBuild(Config{}, dir, nil, nil)
+
+	// 今回のビルドで生成したページ
+	for _, rel := range []string{"page/2.html", "categories/go/page/2.html"} {
+		if err := b.renderPage(filepath.FromSlash(rel), rel, func() ([]byte, error) { return []byte(rel), nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 前回のビルドの残り（記事が減ってページ数が減った・タグがなくなった）
+	for _, rel := range []string{"page/3.html", "categories/go/page/3.html", "tags/old/page/2.html"} {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := (&Service{}).cleanupPagination(b); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path   string
+		exists bool
+	}{
+		{path: "page/2.html", exists: true},
+		{path: "categories/go/page/2.html", exists: true},
+		{path: "page/3.html", exists: false},
+		{path: "categories/go/page/3.html", exists: false},
+		{path: "tags/old/page/2.html", exists: false},
+		{path: "tags/old", exists: false}, // 空になったディレクトリも削除する
+	}
+	for _, tt := range tests {
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(tt.path)))
+		if exists := err == nil; exists != tt.exists {
+			t.Errorf("%s exists = %v, want %v", tt.path, exists, tt.exists)
+		}
+	}
+	if b.result.Deleted != 3 {
+		t.Errorf("deleted = %d, want 3", b.result.Deleted)
+	}
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"time"
+
+	"cms/internal/article"
+)
+
+// previewRoot はプレビューでのサイトルート（ハイライト用CSSはAPIから配信する）
+const previewRoot = "/api/preview/"
+
+// PreviewInput は保存前の記事のプレビュー内容
+type PreviewInput struct {
+	Title      string
+	Slug       string
+	Content    string
+	CategoryID *int64
+	TagIDs     []int64
+}
+
+// PreviewArticle は保存済みの記事（下書きを含む）をエクスポートと同じテンプレートで描画。
+// origin は API サーバーのオリジン（例: http://localhost:8080）で、画像などの URL に使う
+func (s *Service) PreviewArticle(cfg Config, id int64, origin string) ([]byte, error) {
+	a, err := s.articleRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.preview(cfg, *a, origin)
+}
+
+// PreviewContent は保存前の Markdown をエクスポートと同じテンプレートで描画
+func (s *Service) PreviewContent(cfg Config, in PreviewInput, origin string) ([]byte, error) {
+	now := time.Now()
+	a := article.Article{
+		Title:      in.Title,
+		Slug:       in.Slug,
+		Content:    in.Content,
+		Status:     "draft",
+		CategoryID: in.CategoryID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	for _, id := range in.TagIDs {
+		t, err := s.tagRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		a.Tags = append(a.Tags, *t)
+	}
+
+	return s.preview(cfg, a, origin)
+}
+
+// PreviewCSS はプレビューで読み込むハイライト用のスタイルシートを返す
+func (s *Service) PreviewCSS(cfg Config) ([]byte, error) {
+	return highlightCSS(cfg)
+}
+
+func (s *Service) preview(cfg Config, a article.Article, origin string) ([]byte, error) {
+	t, _, err := s.loadTemplates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	b := &build{cfg: cfg, t: t, md: newMarkdown(cfg)}
+
+	// 下書きは公開日時がないため、テンプレートで表示できるよう現在時刻を入れる
+	if a.PublishedAt == nil {
+		now := time.Now()
+		a.PublishedAt = &now
+	}
+
+	// 画像は API（/api/images/）から表示する。管理画面が別オリジンの iframe に
+	// 埋め込んでも読み込めるよう、オリジンを含めた URL にする
+	return s.renderArticle(b, a, origin+apiImagePath, origin+previewRoot)
+}
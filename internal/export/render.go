@@ -0,0 +1,60 @@
+package export
+
+import (
+	"errors"
+	"path"
+)
+
+// ErrNotFound は描画を要求されたファイルがサイトに存在しない場合のエラー
+var ErrNotFound = errors.New("not found")
+
+// Render はエクスポート先に書き出さずに、サイト内のファイル1つ（例: posts/hello.html）だけを
+// エクスポートと同じ処理でメモリ上に描画する（プレビューサーバー用）
+func (s *Service) Render(cfg Config, rel string, includeDrafts bool) ([]byte, error) {
+	rel = path.Clean(rel)
+
+	t, _, err := s.loadTemplates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	b := &build{
+		cfg:  cfg,
+		t:    t,
+		md:   newMarkdown(cfg),
+		only: rel,
+	}
+
+	st, err := s.loadSite(includeDrafts)
+	if err != nil {
+		return nil, err
+	}
+
+	// ページ（要求されたもの以外は描画されない）
+	for _, job := range s.pageJobs(b, st) {
+		if err := job(); err != nil {
+			return nil, err
+		}
+		if b.rendered != nil {
+			return b.rendered, nil
+		}
+	}
+
+	// フィード・スタイルシートなどのページ以外のファイル
+	steps := []func() error{
+		func() error { return s.exportFeeds(b, st) },
+		func() error { return s.exportHighlightCSS(b) },
+		func() error { return s.exportSearchIndex(b, st) },
+		func() error { return s.exportSitemap(b, st) },
+		func() error { return s.exportRobots(b) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+		if b.rendered != nil {
+			return b.rendered, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
@@ -0,0 +1,175 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"cms/internal/article"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"golang.org/x/text/unicode/norm"
+)
+
+// searchIndexFile はクライアントサイド検索用のインデックス
+const searchIndexFile = "search-index.json"
+
+// searchEntry は検索インデックスの1記事分（URL はサイトルートからのパス）
+type searchEntry struct {
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	URL         string    `json:"url"`
+	Tags        []string  `json:"tags"`
+	Category    string    `json:"category,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	Body        string    `json:"body"`
+}
+
+// exportSearchIndex は公開済み記事の検索インデックス（search-index.json）を生成
+func (s *Service) exportSearchIndex(b *build, st *site) error {
+	entries := make([]searchEntry, 0, len(st.articles))
+	for _, a := range st.articles {
+		tags := make([]string, 0, len(a.Tags))
+		for _, t := range a.Tags {
+			tags = append(tags, t.Name)
+		}
+
+		entry := searchEntry{
+			Title:       normalizeText(a.Title),
+			Slug:        a.Slug,
+			URL:         "posts/" + a.Slug + ".html",
+			Tags:        tags,
+			PublishedAt: publishedAt(a),
+			Body:        b.plainText(a),
+		}
+		if a.Category != nil {
+			entry.Category = a.Category.Name
+		}
+		entries = append(entries, entry)
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return b.writeFile(searchIndexFile, data)
+}
+
+// exportSearch は検索ページ（search.html）を描画
+func (s *Service) exportSearch(b *build) error {
+	return b.renderPage("search.html", nil, func() ([]byte, error) {
+		// 検索テンプレート
+		var searchBuf bytes.Buffer
+		err := b.t.ExecuteTemplate(&searchBuf, "search.html", map[string]interface{}{
+			"IndexURL": searchIndexFile,
+			"Root":     "",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// ベーステンプレート
+		return s.renderBase(b, "", "検索", searchBuf.String())
+	})
+}
+
+// plainText は記事本文の Markdown から装飾を除いたテキストを取り出す
+func (b *build) plainText(a article.Article) string {
+	src := []byte(a.Content)
+	doc := b.md.Parser().Parse(text.NewReader(src))
+
+	var w textWriter
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			// 見出しや段落などのブロックの境目は常に空白で区切る
+			if n.Type() == ast.TypeBlock {
+				w.space = true
+			}
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.Text:
+			w.write(string(node.Segment.Value(src)))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				w.breakLine()
+			}
+		case *ast.String:
+			w.write(string(node.Value))
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				seg := lines.At(i)
+				w.write(string(seg.Value(src)))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock, *ast.RawHTML:
+			// HTML タグは検索対象にしない
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return normalizeText(w.String())
+}
+
+// textWriter は段落内の改行をまたぐテキストを連結する。
+// 日本語は改行位置に空白を入れると単語の途中で分かれてしまうため、
+// 前後が CJK の文字同士のときは空白を入れずにつなげる。
+type textWriter struct {
+	buf     strings.Builder
+	space   bool // 直前に空白があったか
+	pending bool // 直前に段落内の改行があったか
+}
+
+func (w *textWriter) write(s string) {
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			w.space = true
+			continue
+		}
+
+		if w.buf.Len() > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(w.buf.String())
+			switch {
+			case w.space:
+				w.buf.WriteByte(' ')
+			case w.pending && !(isCJK(prev) && isCJK(r)):
+				w.buf.WriteByte(' ')
+			}
+		}
+		w.space = false
+		w.pending = false
+		w.buf.WriteRune(r)
+	}
+}
+
+func (w *textWriter) breakLine() {
+	w.pending = true
+}
+
+func (w *textWriter) String() string {
+	return w.buf.String()
+}
+
+// isCJK は日本語・中国語などの空白で区切らない文字かどうか
+func isCJK(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+		return true
+	case r >= 0x3000 && r <= 0x30FF: // 和文の句読点・括弧・長音
+		return true
+	case r >= 0xFF00 && r <= 0xFFEF: // 全角英数字・記号
+		return true
+	}
+	return false
+}
+
+// normalizeText は検索しやすいように NFKC 正規化する（全角英数字→半角、半角カナ→全角 など）
+func normalizeText(s string) string {
+	return norm.NFKC.String(s)
+}
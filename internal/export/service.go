@@ -3,26 +3,32 @@ package export
 import (
 	"bytes"
 	"database/sql"
+	"fmt"
 	"html/template"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"cms/internal/article"
 	"cms/internal/category"
 	"cms/internal/tag"
 	tmpl "cms/internal/template"
+	"cms/internal/user"
 
-	"github.com/yuin/goldmark"
-	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
 )
 
+// exportMu はプロセス内のエクスポートを1つずつ実行させる（API と公開ジョブはそれぞれ別の Service を持つため、
+// パッケージ単位でロックする）。別プロセスとはエクスポート先のロックファイルで排他する
+var exportMu sync.Mutex
+
 type Service struct {
 	articleRepo  *article.Repository
 	categoryRepo *category.Repository
 	tagRepo      *tag.Repository
 	templateRepo *tmpl.Repository
-	md           goldmark.Markdown
 }
 
 func NewService(db *sql.DB) *Service {
@@ -31,9 +37,6 @@ func NewService(db *sql.DB) *Service {
 		categoryRepo: category.NewRepository(db),
 		tagRepo:      tag.NewRepository(db),
 		templateRepo: tmpl.NewRepository(db),
-		md: goldmark.New(
-			goldmark.WithRendererOptions(html.WithUnsafe()),
-		),
 	}
 }
 
@@ -41,256 +44,342 @@ type Config struct {
 	ExportDir string `json:"export_dir"`
 	UploadDir string `json:"upload_dir"`
 	SiteTitle string `json:"site_title"`
+	BaseURL   string `json:"base_url"`
+	AssetPath string `json:"asset_path"` // 画像の出力先（空なら images）
+	RobotsTxt string `json:"robots_txt"`
+	PageSize  int    `json:"page_size"` // 一覧1ページあたりの記事数（0以下なら分割しない）
+
+	HighlightStyle       string `json:"highlight_style"`        // コードブロックのハイライトのスタイル（chroma のスタイル名）
+	HighlightLineNumbers bool   `json:"highlight_line_numbers"` // コードブロックに行番号を付けるか
+
+	MarkdownExtensions []string `json:"markdown_extensions"` // 有効にする Markdown の拡張（nil ならすべて）
+
+	Workers int `json:"-"` // 並列描画数（0以下ならCPU数）
 }
 
-func (s *Service) Export(cfg Config) error {
+// Export は公開済みの記事を HTML に書き出す。編集者以上のみ
+func (s *Service) Export(actor *user.User, cfg Config) (*Result, error) {
+	if err := user.RequireEditor(actor); err != nil {
+		return nil, err
+	}
+
+	// 同時に実行すると互いのステージングディレクトリや出力を壊すため、終了まで待たせる
+	exportMu.Lock()
+	defer exportMu.Unlock()
+
 	// テンプレートをDBからロード
-	t, err := s.loadTemplates()
+	t, sources, err := s.loadTemplates(cfg)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if err := os.MkdirAll(cfg.ExportDir, 0755); err != nil {
+		return nil, err
+	}
+
+	// サーバー・CLI の export・cron の publish-due --export は別プロセスで動くため、
+	// エクスポート先のロックファイルでも排他する
+	unlock, err := lockExportDir(cfg.ExportDir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// ステージングディレクトリに描画し、すべて成功した場合のみ入れ替える
+	entries := generatedEntries(cfg)
+	staging, err := prepareStaging(cfg.ExportDir, entries)
+	if err != nil {
+		return nil, err
 	}
+	defer os.RemoveAll(staging)
 
 	// ディレクトリ作成
 	dirs := []string{
-		cfg.ExportDir,
-		filepath.Join(cfg.ExportDir, "posts"),
-		filepath.Join(cfg.ExportDir, "categories"),
-		filepath.Join(cfg.ExportDir, "tags"),
+		filepath.Join(staging, "posts"),
+		filepath.Join(staging, "categories"),
+		filepath.Join(staging, "tags"),
+		filepath.Join(staging, "css"),
 	}
 	for _, dir := range dirs {
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	// 公開済み記事を取得
-	articles, err := s.articleRepo.GetPublished()
+	// 前回のマニフェストを読み込み、今回のビルドを開始
+	b := newBuild(cfg, staging, t, sources)
+
+	// 公開済み記事・カテゴリ・タグを一括取得
+	st, err := s.loadSite(false)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	articles := st.articles
 
-	// 記事個別ページ生成
-	for _, a := range articles {
-		if err := s.exportArticle(cfg, t, a); err != nil {
-			return err
-		}
+	// ワーカープールで並列に描画（失敗したページがあってもすべて描画してからまとめて返す）
+	if err := runJobs(cfg.Workers, s.pageJobs(b, st)); err != nil {
+		return nil, err
 	}
 
-	// 一覧ページ生成
-	if err := s.exportIndex(cfg, t, articles); err != nil {
-		return err
+	// フィード生成（Atom / RSS）
+	if err := s.exportFeeds(b, st); err != nil {
+		return nil, err
 	}
 
-	// カテゴリ別一覧ページ生成
-	if err := s.exportCategories(cfg, t); err != nil {
-		return err
+	// コードブロックのハイライト用スタイルシート生成
+	if err := s.exportHighlightCSS(b); err != nil {
+		return nil, err
 	}
 
-	// タグ別一覧ページ生成
-	if err := s.exportTags(cfg, t); err != nil {
-		return err
+	// クライアントサイド検索用のインデックス生成
+	if err := s.exportSearchIndex(b, st); err != nil {
+		return nil, err
 	}
 
 	// 不要ファイル削除（下書きに戻した記事のHTMLなど）
-	if err := s.cleanupOrphanedFiles(cfg, articles); err != nil {
-		return err
+	if err := s.cleanupOrphanedFiles(b, articles); err != nil {
+		return nil, err
+	}
+
+	// サイトマップ・robots.txt 生成（削除後のページ構成に合わせる）
+	if err := s.exportSitemap(b, st); err != nil {
+		return nil, err
+	}
+	if err := s.exportRobots(b); err != nil {
+		return nil, err
 	}
 
 	// 画像ファイルをコピー
 	if cfg.UploadDir != "" {
-		if err := s.copyImages(cfg.UploadDir, cfg.ExportDir); err != nil {
-			return err
+		if err := s.copyImages(b); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	// 次回の差分判定のためにマニフェストを保存
+	if err := b.saveManifest(); err != nil {
+		return nil, err
+	}
+
+	// 全ページが描画されたことを確認してから公開中の生成物と入れ替える
+	if err := b.verify(); err != nil {
+		return nil, err
+	}
+	// 画像の出力先が変わった場合は、前回の出力先も入れ替え対象にして削除する
+	if b.prev.AssetDir != "" {
+		entries = appendEntry(entries, b.prev.AssetDir)
+	}
+	if err := swapGenerated(staging, cfg.ExportDir, entries); err != nil {
+		return nil, err
+	}
+
+	return &b.result, nil
 }
 
-func (s *Service) loadTemplates() (*template.Template, error) {
+// pageJobs は全ページの描画ジョブを作成
+func (s *Service) pageJobs(b *build, st *site) []func() error {
+	var jobs []func() error
+	for _, a := range st.articles {
+		jobs = append(jobs, func() error { return s.exportArticle(b, a) })
+	}
+	jobs = append(jobs, s.exportIndex(b, st.articles)...)
+	for _, p := range st.categories {
+		jobs = append(jobs, s.exportCategory(b, p)...)
+	}
+	for _, p := range st.tags {
+		jobs = append(jobs, s.exportTag(b, p)...)
+	}
+	jobs = append(jobs, s.exportArchive(b, st)...)
+	jobs = append(jobs, func() error { return s.exportSearch(b) })
+	return jobs
+}
+
+// loadTemplates はテンプレートを構築し、差分判定用にテンプレート名→内容のマップも返す
+func (s *Service) loadTemplates(cfg Config) (*template.Template, map[string]string, error) {
 	// DBからテンプレートを取得
 	templates, err := s.templateRepo.GetAll()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// template.Templateを構築
-	t := template.New("")
+	t := template.New("").Funcs(templateFuncs(cfg))
+	sources := make(map[string]string, len(templates))
 	for _, tmplData := range templates {
 		_, err := t.New(tmplData.Name + ".html").Parse(tmplData.Content)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		sources[tmplData.Name] = tmplData.Content
+	}
+
+	// 後から追加されたテンプレートがDBにない場合はデフォルトを使う
+	for _, name := range tmpl.AllTemplateNames {
+		if _, ok := sources[name]; ok {
+			continue
+		}
+		content := tmpl.DefaultTemplates[name]
+		if _, err := t.New(name + ".html").Parse(content); err != nil {
+			return nil, nil, err
 		}
+		sources[name] = content
 	}
 
-	return t, nil
+	return t, sources, nil
 }
 
-func (s *Service) exportArticle(cfg Config, t *template.Template, a article.Article) error {
-	// 画像パスを変換: http://localhost:8080/api/images/ → ../images/ (postsフォルダからの相対パス)
-	content := strings.ReplaceAll(a.Content, "http://localhost:8080/api/images/", "../images/")
+// renderContent は Markdown を HTML に変換し、見出しから目次も作る
+// （API を指す画像URLは assetBase 配下に書き換える）
+func (b *build) renderContent(content, assetBase string) (string, []TOCItem, error) {
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	ctx.Set(assetBaseKey, assetBase)
 
-	// Markdown → HTML
-	var contentBuf bytes.Buffer
-	if err := s.md.Convert([]byte(content), &contentBuf); err != nil {
-		return err
-	}
+	src := []byte(content)
+	doc := b.md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
 
-	// 記事テンプレート
-	var articleBuf bytes.Buffer
-	err := t.ExecuteTemplate(&articleBuf, "article.html", map[string]interface{}{
-		"Article": a,
-		"Content": template.HTML(contentBuf.String()),
-	})
-	if err != nil {
-		return err
+	var buf bytes.Buffer
+	if err := b.md.Renderer().Render(&buf, src, doc); err != nil {
+		return "", nil, err
 	}
+	return buf.String(), buildTOC(doc, src), nil
+}
 
-	// ベーステンプレート
-	var finalBuf bytes.Buffer
-	err = t.ExecuteTemplate(&finalBuf, "base.html", map[string]interface{}{
-		"Title":     a.Title,
-		"SiteTitle": cfg.SiteTitle,
-		"Content":   template.HTML(articleBuf.String()),
+func (s *Service) exportArticle(b *build, a article.Article) error {
+	path := filepath.Join("posts", a.Slug+".html")
+	return b.renderPage(path, a, func() ([]byte, error) {
+		// 画像パスは postsフォルダからの相対パス
+		return s.renderArticle(b, a, "../"+assetPath(b.cfg)+"/", "../")
 	})
-	if err != nil {
-		return err
-	}
-
-	// ファイル書き出し
-	path := filepath.Join(cfg.ExportDir, "posts", a.Slug+".html")
-	return os.WriteFile(path, finalBuf.Bytes(), 0644)
 }
 
-func (s *Service) exportIndex(cfg Config, t *template.Template, articles []article.Article) error {
-	// 一覧テンプレート
-	var indexBuf bytes.Buffer
-	err := t.ExecuteTemplate(&indexBuf, "index.html", map[string]interface{}{
-		"Articles": articles,
-	})
+// renderArticle は記事ページを描画（プレビューでも同じ処理を使う）
+func (s *Service) renderArticle(b *build, a article.Article, assetBase, root string) ([]byte, error) {
+	// Markdown → HTML
+	content, toc, err := b.renderContent(a.Content, assetBase)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// ベーステンプレート
-	var finalBuf bytes.Buffer
-	err = t.ExecuteTemplate(&finalBuf, "base.html", map[string]interface{}{
-		"Title":     cfg.SiteTitle,
-		"SiteTitle": cfg.SiteTitle,
-		"Content":   template.HTML(indexBuf.String()),
+	// 記事テンプレート
+	var articleBuf bytes.Buffer
+	err = b.t.ExecuteTemplate(&articleBuf, "article.html", map[string]interface{}{
+		"Article": a,
+		"Content": template.HTML(content),
+		"TOC":     toc,
+		"Root":    root,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// ファイル書き出し
-	path := filepath.Join(cfg.ExportDir, "index.html")
-	return os.WriteFile(path, finalBuf.Bytes(), 0644)
+	// ベーステンプレート
+	return s.renderBase(b, root, a.Title, articleBuf.String())
 }
 
-func (s *Service) exportCategories(cfg Config, t *template.Template) error {
-	categories, err := s.categoryRepo.GetAll()
-	if err != nil {
-		return err
-	}
-
-	for _, c := range categories {
-		articles, err := s.articleRepo.GetByCategory(c.ID)
+func (s *Service) exportIndex(b *build, articles []article.Article) []func() error {
+	l := listing{firstPath: "index.html", pageDir: "", articles: articles}
+	return listingJobs(b, l, nil, func(path string, items []article.Article, p *Pagination) ([]byte, error) {
+		// 一覧テンプレート
+		var indexBuf bytes.Buffer
+		err := b.t.ExecuteTemplate(&indexBuf, "index.html", map[string]interface{}{
+			"Articles":   items,
+			"Pagination": p,
+			"Root":       rootOf(path),
+		})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		// 記事がなければスキップ
-		if len(articles) == 0 {
-			continue
-		}
+		// ベーステンプレート
+		return s.renderBase(b, rootOf(path), pageTitle(b.cfg.SiteTitle, p), indexBuf.String())
+	})
+}
 
+func (s *Service) exportCategory(b *build, cp categoryPage) []func() error {
+	c := cp.Category
+	l := listing{
+		firstPath: "categories/" + c.Slug + ".html",
+		pageDir:   "categories/" + c.Slug,
+		articles:  cp.Articles,
+	}
+	inputs := map[string]interface{}{"Category": c}
+	return listingJobs(b, l, inputs, func(path string, items []article.Article, p *Pagination) ([]byte, error) {
 		// カテゴリテンプレート
 		var categoryBuf bytes.Buffer
-		err = t.ExecuteTemplate(&categoryBuf, "category.html", map[string]interface{}{
-			"Category": c,
-			"Articles": articles,
+		err := b.t.ExecuteTemplate(&categoryBuf, "category.html", map[string]interface{}{
+			"Category":   c,
+			"Articles":   items,
+			"Pagination": p,
+			"Root":       rootOf(path),
 		})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		// ベーステンプレート
-		var finalBuf bytes.Buffer
-		err = t.ExecuteTemplate(&finalBuf, "base.html", map[string]interface{}{
-			"Title":     "カテゴリ: " + c.Name,
-			"SiteTitle": cfg.SiteTitle,
-			"Content":   template.HTML(categoryBuf.String()),
-		})
-		if err != nil {
-			return err
-		}
-
-		// ファイル書き出し
-		path := filepath.Join(cfg.ExportDir, "categories", c.Slug+".html")
-		if err := os.WriteFile(path, finalBuf.Bytes(), 0644); err != nil {
-			return err
-		}
-	}
-
-	return nil
+		return s.renderBase(b, rootOf(path), pageTitle("カテゴリ: "+c.Name, p), categoryBuf.String())
+	})
 }
 
-func (s *Service) exportTags(cfg Config, t *template.Template) error {
-	tags, err := s.tagRepo.GetAll()
-	if err != nil {
-		return err
+func (s *Service) exportTag(b *build, tp tagPage) []func() error {
+	tg := tp.Tag
+	l := listing{
+		firstPath: "tags/" + tg.Slug + ".html",
+		pageDir:   "tags/" + tg.Slug,
+		articles:  tp.Articles,
 	}
-
-	for _, tg := range tags {
-		articles, err := s.articleRepo.GetByTag(tg.ID)
-		if err != nil {
-			return err
-		}
-
-		// 記事がなければスキップ
-		if len(articles) == 0 {
-			continue
-		}
-
+	inputs := map[string]interface{}{"Tag": tg}
+	return listingJobs(b, l, inputs, func(path string, items []article.Article, p *Pagination) ([]byte, error) {
 		// タグテンプレート
 		var tagBuf bytes.Buffer
-		err = t.ExecuteTemplate(&tagBuf, "tag.html", map[string]interface{}{
-			"Tag":      tg,
-			"Articles": articles,
+		err := b.t.ExecuteTemplate(&tagBuf, "tag.html", map[string]interface{}{
+			"Tag":        tg,
+			"Articles":   items,
+			"Pagination": p,
+			"Root":       rootOf(path),
 		})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		// ベーステンプレート
-		var finalBuf bytes.Buffer
-		err = t.ExecuteTemplate(&finalBuf, "base.html", map[string]interface{}{
-			"Title":     "タグ: " + tg.Name,
-			"SiteTitle": cfg.SiteTitle,
-			"Content":   template.HTML(tagBuf.String()),
-		})
-		if err != nil {
-			return err
-		}
+		return s.renderBase(b, rootOf(path), pageTitle("タグ: "+tg.Name, p), tagBuf.String())
+	})
+}
 
-		// ファイル書き出し
-		path := filepath.Join(cfg.ExportDir, "tags", tg.Slug+".html")
-		if err := os.WriteFile(path, finalBuf.Bytes(), 0644); err != nil {
-			return err
-		}
+// renderBase はページ本文をベーステンプレートで包む（root はサイトのルートへのパス）
+func (s *Service) renderBase(b *build, root, title, content string) ([]byte, error) {
+	var finalBuf bytes.Buffer
+	err := b.t.ExecuteTemplate(&finalBuf, "base.html", map[string]interface{}{
+		"Title":     title,
+		"SiteTitle": b.cfg.SiteTitle,
+		"Content":   template.HTML(content),
+		"Root":      root,
+	})
+	if err != nil {
+		return nil, err
 	}
+	return finalBuf.Bytes(), nil
+}
 
-	return nil
+// pageTitle は2ページ目以降のタイトルにページ番号を付ける
+func pageTitle(title string, p *Pagination) string {
+	if p.Page <= 1 {
+		return title
+	}
+	return fmt.Sprintf("%s (%d/%d)", title, p.Page, p.TotalPages)
 }
 
-func (s *Service) copyImages(uploadDir, exportDir string) error {
+func (s *Service) copyImages(b *build) error {
+	uploadDir := b.cfg.UploadDir
+
 	// uploadsディレクトリが存在しない場合はスキップ
 	if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
 		return nil
 	}
 
-	// 出力先のimagesディレクトリを作成
-	imagesDir := filepath.Join(exportDir, "images")
+	// 出力先の画像ディレクトリを作成
+	imagesDir := filepath.Join(b.dir, filepath.FromSlash(assetPath(b.cfg)))
 	if err := os.MkdirAll(imagesDir, 0755); err != nil {
 		return err
 	}
@@ -306,10 +395,12 @@ func (s *Service) copyImages(uploadDir, exportDir string) error {
 			continue
 		}
 
-		srcPath := filepath.Join(uploadDir, entry.Name())
-		dstPath := filepath.Join(imagesDir, entry.Name())
-
-		if err := copyFile(srcPath, dstPath); err != nil {
+		// 内容が同じ画像は書き込まない
+		data, err := os.ReadFile(filepath.Join(uploadDir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := b.writeFile(filepath.Join(filepath.FromSlash(assetPath(b.cfg)), entry.Name()), data); err != nil {
 			return err
 		}
 	}
@@ -317,7 +408,7 @@ func (s *Service) copyImages(uploadDir, exportDir string) error {
 	return nil
 }
 
-func (s *Service) cleanupOrphanedFiles(cfg Config, publishedArticles []article.Article) error {
+func (s *Service) cleanupOrphanedFiles(b *build, publishedArticles []article.Article) error {
 	// 公開済み記事のslugセットを作成
 	publishedSlugs := make(map[string]bool)
 	for _, a := range publishedArticles {
@@ -338,25 +429,71 @@ func (s *Service) cleanupOrphanedFiles(cfg Config, publishedArticles []article.A
 	}
 
 	// posts/ディレクトリの不要ファイルを削除
-	if err := s.cleanupDirectory(cfg.ExportDir, "posts", publishedSlugs); err != nil {
+	if err := s.cleanupDirectory(b, "posts", publishedSlugs); err != nil {
 		return err
 	}
 
 	// categories/ディレクトリの不要ファイルを削除
-	if err := s.cleanupDirectory(cfg.ExportDir, "categories", publishedCategorySlugs); err != nil {
+	if err := s.cleanupDirectory(b, "categories", publishedCategorySlugs); err != nil {
 		return err
 	}
 
 	// tags/ディレクトリの不要ファイルを削除
-	if err := s.cleanupDirectory(cfg.ExportDir, "tags", publishedTagSlugs); err != nil {
+	if err := s.cleanupDirectory(b, "tags", publishedTagSlugs); err != nil {
+		return err
+	}
+
+	// アーカイブのうち記事がなくなった年・月のページを削除
+	if err := s.cleanupUngenerated(b, "archive"); err != nil {
+		return err
+	}
+
+	// 一覧の2ページ目以降（page/ 以下）のうち今回生成しなかったものを削除
+	if err := s.cleanupPagination(b); err != nil {
 		return err
 	}
 
+	// ベースURL未設定時はサイトマップとフィードを生成しないため、古いものを削除
+	if b.cfg.BaseURL == "" {
+		if err := s.cleanupFeeds(b); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (s *Service) cleanupDirectory(exportDir, subdir string, validSlugs map[string]bool) error {
-	dir := filepath.Join(exportDir, subdir)
+// cleanupFeeds はサイトマップ・サイト全体のフィード・カテゴリ別/タグ別フィードを削除
+func (s *Service) cleanupFeeds(b *build) error {
+	for _, name := range []string{"sitemap.xml", "feed.xml", "rss.xml"} {
+		if err := b.removeFile(filepath.Join(b.dir, name)); err != nil {
+			return err
+		}
+	}
+
+	for _, subdir := range []string{"categories", "tags"} {
+		entries, err := os.ReadDir(filepath.Join(b.dir, subdir))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".xml" {
+				continue
+			}
+			if err := b.removeFile(filepath.Join(b.dir, subdir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) cleanupDirectory(b *build, subdir string, validSlugs map[string]bool) error {
+	dir := filepath.Join(b.dir, subdir)
 
 	// ディレクトリが存在しない場合はスキップ
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -373,18 +510,19 @@ func (s *Service) cleanupDirectory(exportDir, subdir string, validSlugs map[stri
 			continue
 		}
 
-		// .htmlファイルのみ処理
-		if !strings.HasSuffix(entry.Name(), ".html") {
+		// .html（ページ）と .xml（フィード）のみ処理
+		ext := filepath.Ext(entry.Name())
+		if ext != ".html" && ext != ".xml" {
 			continue
 		}
 
 		// slugを抽出（例: "article-slug.html" → "article-slug"）
-		slug := strings.TrimSuffix(entry.Name(), ".html")
+		slug := strings.TrimSuffix(entry.Name(), ext)
 
 		// 公開済みリストに含まれていない場合は削除
 		if !validSlugs[slug] {
 			filePath := filepath.Join(dir, entry.Name())
-			if err := os.Remove(filePath); err != nil {
+			if err := b.removeFile(filePath); err != nil {
 				return err
 			}
 		}
@@ -392,20 +530,3 @@ func (s *Service) cleanupDirectory(exportDir, subdir string, validSlugs map[stri
 
 	return nil
 }
-
-func copyFile(src, dst string) error {
-	srcFile, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer srcFile.Close()
-
-	dstFile, err := os.Create(dst)
-	if err != nil {
-		return err
-	}
-	defer dstFile.Close()
-
-	_, err = dstFile.ReadFrom(srcFile)
-	return err
-}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"sort"
+
+	"cms/internal/article"
+	"cms/internal/category"
+	"cms/internal/tag"
+)
+
+// categoryPage はカテゴリ別一覧ページ1枚分のデータ
+type categoryPage struct {
+	Category category.Category
+	Articles []article.Article
+}
+
+// tagPage はタグ別一覧ページ1枚分のデータ
+type tagPage struct {
+	Tag      tag.Tag
+	Articles []article.Article
+}
+
+// site はエクスポート対象の全データ（DBからは一度だけ読み込み、メモリ上でグループ化する）
+type site struct {
+	articles   []article.Article
+	categories []categoryPage
+	tags       []tagPage
+	archive    []ArchiveYear
+}
+
+// loadSite は公開済み記事・カテゴリ・タグを読み込み、カテゴリ別・タグ別にまとめる
+// （includeDrafts が true なら下書きも含める。プレビュー用）
+func (s *Service) loadSite(includeDrafts bool) (*site, error) {
+	var articles []article.Article
+	var err error
+	if includeDrafts {
+		articles, err = s.loadAllArticles()
+	} else {
+		articles, err = s.articleRepo.GetPublished()
+	}
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// 公開日時の降順を保ったままグループ化
+	byCategory := make(map[int64][]article.Article)
+	byTag := make(map[int64][]article.Article)
+	for _, a := range articles {
+		if a.CategoryID != nil {
+			byCategory[*a.CategoryID] = append(byCategory[*a.CategoryID], a)
+		}
+		for _, t := range a.Tags {
+			byTag[t.ID] = append(byTag[t.ID], a)
+		}
+	}
+
+	st := &site{articles: articles, archive: groupArchive(articles)}
+
+	// 記事がないカテゴリ・タグのページは生成しない
+	for _, c := range categories {
+		if len(byCategory[c.ID]) > 0 {
+			st.categories = append(st.categories, categoryPage{Category: c, Articles: byCategory[c.ID]})
+		}
+	}
+	for _, t := range tags {
+		if len(byTag[t.ID]) > 0 {
+			st.tags = append(st.tags, tagPage{Tag: t, Articles: byTag[t.ID]})
+		}
+	}
+
+	return st, nil
+}
+
+// loadAllArticles は下書きを含む全記事を公開済み記事と同じ順（公開日時の降順）で返す。
+// 下書きには公開日時がないため、更新日時を公開日時として扱う
+func (s *Service) loadAllArticles() ([]article.Article, error) {
+	articles, err := s.articleRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range articles {
+		if articles[i].PublishedAt == nil {
+			updatedAt := articles[i].UpdatedAt
+			articles[i].PublishedAt = &updatedAt
+		}
+	}
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articles[i].PublishedAt.After(*articles[j].PublishedAt)
+	})
+
+	return articles, nil
+}
@@ -0,0 +1,107 @@
+package export
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// exportSitemap は生成済みの全ページを列挙した sitemap.xml を生成
+func (s *Service) exportSitemap(b *build, st *site) error {
+	cfg := b.cfg
+	articles := st.articles
+
+	// サイトマップは絶対URLが必須のため、ベースURL未設定時は生成しない
+	if cfg.BaseURL == "" {
+		return nil
+	}
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+
+	urlSet := sitemapURLSet{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	add := func(loc string, lastMod time.Time) {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:     loc,
+			LastMod: lastMod.Format(time.RFC3339),
+		})
+	}
+
+	// 一覧ページ（2ページ目以降も含む）
+	addListing := func(l listing, firstURL string) {
+		for i, items := range l.pages(cfg.PageSize) {
+			loc := firstURL
+			if i > 0 {
+				loc = pageURL(baseURL, l.pagePath(i+1))
+			}
+			add(loc, latestUpdate(items))
+		}
+	}
+
+	// トップページ
+	addListing(listing{firstPath: "index.html", articles: articles}, baseURL+"/index.html")
+
+	// 記事個別ページ
+	for _, a := range articles {
+		add(postURL(baseURL, a), a.UpdatedAt)
+	}
+
+	// カテゴリ別一覧ページ（公開済み記事があるもののみ生成されている）
+	for _, p := range st.categories {
+		l := listing{pageDir: "categories/" + p.Category.Slug, articles: p.Articles}
+		addListing(l, categoryURL(baseURL, p.Category))
+	}
+
+	// タグ別一覧ページ
+	for _, p := range st.tags {
+		l := listing{pageDir: "tags/" + p.Tag.Slug, articles: p.Articles}
+		addListing(l, tagURL(baseURL, p.Tag))
+	}
+
+	// アーカイブ（全体・年別・月別）
+	add(baseURL+"/archive/index.html", latestUpdate(articles))
+	for _, y := range st.archive {
+		add(pageURL(baseURL, y.URL), latestUpdate(y.articles))
+		for _, m := range y.Months {
+			add(pageURL(baseURL, m.URL), latestUpdate(m.articles))
+		}
+	}
+
+	return writeXML(b, "sitemap.xml", urlSet)
+}
+
+// pageURL はサイトルートからのパスを絶対URLにする（各セグメントをエスケープ）
+func pageURL(baseURL, rel string) string {
+	segments := strings.Split(rel, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	return baseURL + "/" + strings.Join(segments, "/")
+}
+
+// exportRobots は robots.txt を生成（設定が空ならデフォルト内容を使う）
+func (s *Service) exportRobots(b *build) error {
+	cfg := b.cfg
+
+	content := strings.TrimSpace(cfg.RobotsTxt)
+	if content == "" {
+		content = "User-agent: *\nAllow: /"
+	}
+
+	// サイトマップの場所を追記（記述済みの場合はそのまま）
+	if cfg.BaseURL != "" && !strings.Contains(strings.ToLower(content), "sitemap:") {
+		content += "\n\nSitemap: " + strings.TrimSuffix(cfg.BaseURL, "/") + "/sitemap.xml"
+	}
+
+	return b.writeFile("robots.txt", []byte(content+"\n"))
+}
@@ -0,0 +1,196 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// エクスポート先に作成する一時ディレクトリ（ステージング・退避用）の接頭辞と、
+// 別プロセスのエクスポートと排他するためのロックファイル
+const (
+	stagingPrefix = ".cms-staging-"
+	backupPrefix  = ".cms-backup-"
+	lockFile      = ".cms-export.lock"
+)
+
+// generatedEntries はエクスポートが管理するトップレベルのファイル・ディレクトリ
+// （これ以外の .git や CNAME などには触れない）
+func generatedEntries(cfg Config) []string {
+	entries := []string{
+		manifestFile,
+		"index.html",
+		"feed.xml",
+		"rss.xml",
+		"sitemap.xml",
+		"robots.txt",
+		"search.html",
+		searchIndexFile,
+		"posts",
+		"categories",
+		"tags",
+		"page",
+		"archive",
+		"css",
+	}
+
+	// 画像の出力先はトップレベルのディレクトリ単位で管理する
+	return appendEntry(entries, assetDir(cfg))
+}
+
+// assetDir は画像の出力先のトップレベルのディレクトリ名
+func assetDir(cfg Config) string {
+	return strings.SplitN(assetPath(cfg), "/", 2)[0]
+}
+
+// appendEntry は entries に name が含まれていなければ追加する
+func appendEntry(entries []string, name string) []string {
+	for _, e := range entries {
+		if e == name {
+			return entries
+		}
+	}
+	return append(entries, name)
+}
+
+// prepareStaging はエクスポート先の中にステージングディレクトリを作成し、
+// 現在の生成物をハードリンクで複製する（差分判定と mtime の維持のため）
+func prepareStaging(exportDir string, generated []string) (string, error) {
+	// 前回異常終了したときのステージング・退避ディレクトリを削除
+	// （呼び出し元はエクスポート先のロックを保持しているため、実行中のエクスポートのものは存在しない。
+	// 入れ替えの途中で止まって欠けた生成物は、今回のエクスポートですべて描画し直される）
+	entries, err := os.ReadDir(exportDir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && (strings.HasPrefix(entry.Name(), stagingPrefix) || strings.HasPrefix(entry.Name(), backupPrefix)) {
+			if err := os.RemoveAll(filepath.Join(exportDir, entry.Name())); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	staging, err := os.MkdirTemp(exportDir, stagingPrefix)
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range generated {
+		src := filepath.Join(exportDir, name)
+		if _, err := os.Lstat(src); os.IsNotExist(err) {
+			continue
+		}
+		if err := linkTree(src, filepath.Join(staging, name)); err != nil {
+			os.RemoveAll(staging)
+			return "", err
+		}
+	}
+
+	return staging, nil
+}
+
+// linkTree は src 以下をハードリンク（できない場合はコピー）で dst に複製
+func linkTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if err := os.Link(path, target); err == nil {
+			return nil
+		}
+		return copyFilePreserve(path, target, info)
+	})
+}
+
+// copyFilePreserve はファイルをコピーし、mtime も元ファイルに合わせる
+func copyFilePreserve(src, dst string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// swapGenerated はエクスポート先の生成物をステージングの内容と入れ替える。
+// 途中で失敗した場合は元の状態に戻す。
+func swapGenerated(staging, exportDir string, generated []string) error {
+	backup, err := os.MkdirTemp(exportDir, backupPrefix)
+	if err != nil {
+		return err
+	}
+
+	var movedOut, movedIn []string
+	rollback := func() {
+		for _, name := range movedIn {
+			os.RemoveAll(filepath.Join(exportDir, name))
+		}
+		restored := true
+		for _, name := range movedOut {
+			if err := os.Rename(filepath.Join(backup, name), filepath.Join(exportDir, name)); err != nil {
+				restored = false
+			}
+		}
+		// 戻せなかった場合は退避先を残す（次回のエクスポートまでは手動で復旧できるように）
+		if restored {
+			os.RemoveAll(backup)
+		}
+	}
+
+	// 現在の生成物を退避
+	for _, name := range generated {
+		path := filepath.Join(exportDir, name)
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.Rename(path, filepath.Join(backup, name)); err != nil {
+			rollback()
+			return fmt.Errorf("failed to move %s aside: %w", name, err)
+		}
+		movedOut = append(movedOut, name)
+	}
+
+	// ステージングの生成物を配置
+	for _, name := range generated {
+		path := filepath.Join(staging, name)
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.Rename(path, filepath.Join(exportDir, name)); err != nil {
+			rollback()
+			return fmt.Errorf("failed to move %s into place: %w", name, err)
+		}
+		movedIn = append(movedIn, name)
+	}
+
+	return os.RemoveAll(backup)
+}
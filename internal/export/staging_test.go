@@ -0,0 +1,39 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrepareStagingRemovesLeftovers(t *testing.T) {
+	exportDir := t.TempDir()
+	for _, name := range []string{".cms-staging-123", ".cms-backup-456", ".git", "posts"} {
+		if err := os.MkdirAll(filepath.Join(exportDir, name, "sub"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	staging, err := prepareStaging(exportDir, []string{"posts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path   string
+		exists bool
+	}{
+		{path: ".cms-staging-123", exists: false},
+		{path: ".cms-backup-456", exists: false},
+		{path: ".git", exists: true}, // エクスポートが生成しないものには触れない
+		{path: "posts", exists: true},
+		{path: filepath.Base(staging), exists: true},
+		{path: filepath.Join(filepath.Base(staging), "posts", "sub"), exists: true},
+	}
+	for _, tt := range tests {
+		_, err := os.Stat(filepath.Join(exportDir, tt.path))
+		if exists := err == nil; exists != tt.exists {
+			t.Errorf("%s exists = %v, want %v", tt.path, exists, tt.exists)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package export
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+	"golang.org/x/text/unicode/norm"
+)
+
+// TOCItem は記事の目次の1項目（見出しのレベルに応じて入れ子になる）
+type TOCItem struct {
+	Title    string    `json:"title"`
+	ID       string    `json:"id"`
+	Level    int       `json:"level"`
+	Children []TOCItem `json:"children,omitempty"`
+}
+
+// headingIDs は見出しのIDを生成する。goldmark の既定は英数字以外を捨てるため
+// 日本語の見出しがすべて "heading" になってしまうので、文字はそのまま残す。
+// 同じ見出しが複数ある場合は -1, -2 ... を付ける（記事ごとに作り直す）。
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: map[string]bool{}}
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	id := headingSlug(string(value))
+	if id == "" {
+		id = "heading"
+	}
+
+	unique := id
+	for i := 1; h.used[unique]; i++ {
+		unique = id + "-" + strconv.Itoa(i)
+	}
+	h.used[unique] = true
+	return []byte(unique)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
+
+// headingSlug は見出しの文字列から ID を作る
+// （NFKC 正規化・小文字化し、文字と数字以外は除いて空白は - にする）
+func headingSlug(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(norm.NFKC.String(s)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' || r == 'ー':
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-':
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// buildTOC は記事の見出しから目次を作る
+func buildTOC(doc ast.Node, src []byte) []TOCItem {
+	var root []TOCItem
+
+	// 現在の階層をたどるためのスタック（各要素は親の Children を指す）
+	type level struct {
+		level int
+		items *[]TOCItem
+	}
+	stack := []level{{level: 0, items: &root}}
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		heading, ok := n.(*ast.Heading)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		id, _ := heading.AttributeString("id")
+		idBytes, _ := id.([]byte)
+		item := TOCItem{
+			Title: headingText(heading, src),
+			ID:    string(idBytes),
+			Level: heading.Level,
+		}
+
+		for len(stack) > 1 && stack[len(stack)-1].level >= heading.Level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1].items
+		*parent = append(*parent, item)
+		last := &(*parent)[len(*parent)-1]
+		stack = append(stack, level{level: heading.Level, items: &last.Children})
+
+		return ast.WalkSkipChildren, nil
+	})
+
+	return root
+}
+
+// headingText は見出しの装飾を除いた文字列を返す
+func headingText(n ast.Node, src []byte) string {
+	var w textWriter
+	ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Text:
+			w.write(string(node.Segment.Value(src)))
+		case *ast.String:
+			w.write(string(node.Value))
+		}
+		return ast.WalkContinue, nil
+	})
+	return w.String()
+}
@@ -0,0 +1,223 @@
+package export
+
+import (
+	"bytes"
+	stdhtml "html"
+	"html/template"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// apiImagePath は管理画面がアップロード画像の参照に使う API のパス
+const apiImagePath = "/api/images/"
+
+// defaultAssetPath は画像の出力先（エクスポート先からの相対パス）の既定値
+const defaultAssetPath = "images"
+
+// assetBaseKey は Markdown 変換時に画像の置き換え先を渡すためのコンテキストキー
+var assetBaseKey = parser.NewContextKey()
+
+// htmlURLAttr は生の HTML の中の URL を持つ属性（値は引用符付き・なしのどちらも）
+var htmlURLAttr = regexp.MustCompile(`(?i)(\s(?:src|href)\s*=\s*)("[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+)`)
+
+// apiURLRewriter は API を指す画像・リンクの URL をエクスポート後の画像パスに書き換える。
+// Markdown に直接書かれた HTML（ブロック・インライン）の src・href も対象にする
+type apiURLRewriter struct{}
+
+func (r *apiURLRewriter) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	assetBase, _ := pc.Get(assetBaseKey).(string)
+	if assetBase == "" {
+		return
+	}
+	source := reader.Source()
+
+	// 生の HTML は書き換えたノードに置き換えるため、走査が終わってから置き換える
+	var replaces [][2]ast.Node
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Image:
+			node.Destination = rewriteAPIURL(node.Destination, assetBase)
+		case *ast.Link:
+			node.Destination = rewriteAPIURL(node.Destination, assetBase)
+		case *ast.HTMLBlock:
+			var raw []byte
+			for i := 0; i < node.Lines().Len(); i++ {
+				line := node.Lines().At(i)
+				raw = append(raw, line.Value(source)...)
+			}
+			if node.HasClosure() {
+				closure := node.ClosureLine
+				raw = append(raw, closure.Value(source)...)
+			}
+			if html, ok := rewriteHTMLURLs(raw, assetBase); ok {
+				block := &rawHTMLBlock{value: html}
+				block.SetBlankPreviousLines(node.HasBlankPreviousLines())
+				replaces = append(replaces, [2]ast.Node{node, block})
+			}
+		case *ast.RawHTML:
+			var raw []byte
+			for i := 0; i < node.Segments.Len(); i++ {
+				segment := node.Segments.At(i)
+				raw = append(raw, segment.Value(source)...)
+			}
+			if html, ok := rewriteHTMLURLs(raw, assetBase); ok {
+				replaces = append(replaces, [2]ast.Node{node, &rawHTMLInline{value: html}})
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, r := range replaces {
+		r[0].Parent().ReplaceChild(r[0].Parent(), r[0], r[1])
+	}
+}
+
+// rewriteHTMLURLs は生の HTML の src・href を書き換える（書き換えた箇所がなければ false）
+func rewriteHTMLURLs(raw []byte, assetBase string) ([]byte, bool) {
+	changed := false
+	html := htmlURLAttr.ReplaceAllFunc(raw, func(m []byte) []byte {
+		sub := htmlURLAttr.FindSubmatch(m)
+		prefix, value := sub[1], sub[2]
+
+		quote := ""
+		if value[0] == '"' || value[0] == '\'' {
+			quote = string(value[0])
+			value = value[1 : len(value)-1]
+		}
+		// 属性値の &amp; などは URL として解釈する前に戻す
+		dest := []byte(stdhtml.UnescapeString(string(value)))
+		rewritten := rewriteAPIURL(dest, assetBase)
+		if bytes.Equal(rewritten, dest) {
+			return m
+		}
+
+		changed = true
+		return []byte(string(prefix) + quote + stdhtml.EscapeString(string(rewritten)) + quote)
+	})
+	return html, changed
+}
+
+// rawHTMLBlock・rawHTMLInline は画像 URL を書き換えた生の HTML
+// （元の HTML ブロック・インライン HTML はソースの位置しか持たないため、書き換えた内容を持つノードに置き換える）
+var (
+	kindRawHTMLBlock  = ast.NewNodeKind("RawHTMLBlock")
+	kindRawHTMLInline = ast.NewNodeKind("RawHTMLInline")
+)
+
+type rawHTMLBlock struct {
+	ast.BaseBlock
+	value []byte
+}
+
+func (n *rawHTMLBlock) Kind() ast.NodeKind { return kindRawHTMLBlock }
+
+func (n *rawHTMLBlock) IsRaw() bool { return true }
+
+func (n *rawHTMLBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Value": string(n.value)}, nil)
+}
+
+type rawHTMLInline struct {
+	ast.BaseInline
+	value []byte
+}
+
+func (n *rawHTMLInline) Kind() ast.NodeKind { return kindRawHTMLInline }
+
+func (n *rawHTMLInline) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Value": string(n.value)}, nil)
+}
+
+// rawHTMLRenderer は書き換えた生の HTML をそのまま出力する
+type rawHTMLRenderer struct{}
+
+func (r *rawHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindRawHTMLBlock, r.render)
+	reg.Register(kindRawHTMLInline, r.render)
+}
+
+func (r *rawHTMLRenderer) render(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	switch node := n.(type) {
+	case *rawHTMLBlock:
+		_, _ = w.Write(node.value)
+	case *rawHTMLInline:
+		_, _ = w.Write(node.value)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// rewriteAPIURL は API の画像 URL（ホストの有無・ホスト名は問わない）であれば assetBase 配下に置き換える。
+// サーバーのホスト・ポートは環境によって変わるため、パスが /api/images/ で始まるかどうかで判定する
+func rewriteAPIURL(dest []byte, assetBase string) []byte {
+	u, err := url.Parse(string(dest))
+	if err != nil {
+		return dest
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return dest
+	}
+	if u.Opaque != "" || !strings.HasPrefix(u.Path, apiImagePath) {
+		return dest
+	}
+
+	rewritten := assetBase + strings.TrimPrefix(u.EscapedPath(), apiImagePath)
+	if u.Fragment != "" {
+		rewritten += "#" + u.EscapedFragment()
+	}
+	return []byte(rewritten)
+}
+
+// assetPath は画像の出力先（前後のスラッシュなし）を返す
+func assetPath(cfg Config) string {
+	p := strings.Trim(cfg.AssetPath, "/")
+	if p == "" {
+		return defaultAssetPath
+	}
+	return p
+}
+
+// templateFuncs はテンプレートで使えるURLヘルパー
+//
+//	absURL "posts/hello.html" → https://example.github.io/blog/posts/hello.html
+//	relURL "posts/hello.html" → /blog/posts/hello.html
+func templateFuncs(cfg Config) template.FuncMap {
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+
+	basePath := ""
+	if u, err := url.Parse(baseURL); err == nil {
+		basePath = strings.TrimSuffix(u.Path, "/")
+	}
+
+	return template.FuncMap{
+		"absURL": func(path string) string {
+			if isAbsoluteURL(path) {
+				return path
+			}
+			return baseURL + "/" + strings.TrimPrefix(path, "/")
+		},
+		"relURL": func(path string) string {
+			if isAbsoluteURL(path) {
+				return path
+			}
+			return basePath + "/" + strings.TrimPrefix(path, "/")
+		},
+	}
+}
+
+func isAbsoluteURL(path string) bool {
+	u, err := url.Parse(path)
+	return err == nil && u.IsAbs()
+}
@@ -0,0 +1,92 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteAPIURL(t *testing.T) {
+	tests := []struct {
+		dest string
+		want string
+	}{
+		{dest: "/api/images/a.png", want: "../images/a.png"},
+		{dest: "http://localhost:8080/api/images/a.png", want: "../images/a.png"},
+		{dest: "https://cms.example.com/api/images/dir/a%20b.png#top", want: "../images/dir/a%20b.png#top"},
+		{dest: "/api/images/a.png?w=100", want: "../images/a.png"},
+		{dest: "/api/articles/1", want: "/api/articles/1"},
+		{dest: "https://example.com/images/a.png", want: "https://example.com/images/a.png"},
+		{dest: "images/a.png", want: "images/a.png"},
+		{dest: "mailto:a@example.com", want: "mailto:a@example.com"},
+		{dest: "ftp://example.com/api/images/a.png", want: "ftp://example.com/api/images/a.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dest, func(t *testing.T) {
+			if got := string(rewriteAPIURL([]byte(tt.dest), "../images/")); got != tt.want {
+				t.Errorf("rewriteAPIURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderContentRewritesAPIURLs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "markdown image and link",
+			content: "![a](http://cms.example.com:9000/api/images/a.png) [b](/api/images/b.png)",
+			want:    []string{`src="../images/a.png"`, `href="../images/b.png"`},
+		},
+		{
+			name:    "html block",
+			content: "<figure>\n<img src=\"http://localhost:8080/api/images/a.png\" alt=\"a\">\n<a href='/api/images/b.png'>b</a>\n</figure>",
+			want:    []string{"<figure>", `<img src="../images/a.png" alt="a">`, `<a href='../images/b.png'>b</a>`, "</figure>"},
+		},
+		{
+			name:    "html block with closure",
+			content: "<!--\n<img src=\"/api/images/a.png\">\n-->",
+			want:    []string{"<!--\n", `<img src="../images/a.png">`, "\n-->"},
+		},
+		{
+			name:    "inline html",
+			content: "text <img src=/api/images/a.png width=10> <a href=\"/api/images/b.png?x=1&amp;y=2#top\">b</a> text",
+			want:    []string{`<p>text <img src=../images/a.png width=10> <a href="../images/b.png#top">b</a> text</p>`},
+		},
+		{
+			name:    "other html is unchanged",
+			content: "<div data-src=\"/api/images/a.png\"><img src=\"https://example.com/a.png\"></div>",
+			want:    []string{`<div data-src="/api/images/a.png"><img src="https://example.com/a.png"></div>`},
+		},
+		{
+			name:    "code is not rewritten",
+			content: "`<img src=\"/api/images/a.png\">`\n\n```\n![a](/api/images/a.png)\n```",
+			want:    []string{"&lt;img src=&quot;/api/images/a.png&quot;&gt;", "![a](/api/images/a.png)"},
+			notWant: []string{"../images/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBuild(Config{}, t.TempDir(), nil, nil)
+			html, _, err := b.renderContent(tt.content, "../images/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(html, want) {
+					t.Errorf("html does not contain %q:\n%s", want, html)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(html, notWant) {
+					t.Errorf("html contains %q:\n%s", notWant, html)
+				}
+			}
+		})
+	}
+}
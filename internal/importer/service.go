@@ -7,10 +7,12 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"cms/internal/article"
 	"cms/internal/category"
 	"cms/internal/tag"
+	"cms/internal/user"
 
 	"github.com/goccy/go-yaml"
 )
@@ -21,7 +23,10 @@ type FrontMatter struct {
 	Slug     string   `yaml:"slug"`
 	Category string   `yaml:"category"`
 	Tags     []string `yaml:"tags"`
-	Status   string   `yaml:"status"` // draft or published
+	Status   string   `yaml:"status"` // draft, in_review, approved, published or scheduled
+	// 元の公開日時・更新日時（移行元のブログの日付を残す）
+	PublishedAt *time.Time `yaml:"published_at"`
+	UpdatedAt   *time.Time `yaml:"updated_at"`
 }
 
 type Service struct {
@@ -38,8 +43,8 @@ func NewService(db *sql.DB) *Service {
 	}
 }
 
-// ImportMarkdown はMarkdownファイルを解析してDBに保存
-func (s *Service) ImportMarkdown(filePath string) (*article.Article, error) {
+// ImportMarkdown はMarkdownファイルを解析して actor を作成者とする記事としてDBに保存
+func (s *Service) ImportMarkdown(actor *user.User, filePath string) (*article.Article, error) {
 	// ファイル読み込み
 	content, err := os.ReadFile(filePath)
 	if err != nil {
@@ -67,7 +72,7 @@ func (s *Service) ImportMarkdown(filePath string) (*article.Article, error) {
 	// カテゴリ解決（名前からIDを取得、なければ作成）
 	var categoryID *int64
 	if frontMatter.Category != "" {
-		cat, err := s.findOrCreateCategory(frontMatter.Category)
+		cat, err := s.findOrCreateCategory(actor, frontMatter.Category)
 		if err != nil {
 			return nil, fmt.Errorf("カテゴリ解決エラー: %w", err)
 		}
@@ -77,35 +82,50 @@ func (s *Service) ImportMarkdown(filePath string) (*article.Article, error) {
 	// タグ解決（名前からIDを取得、なければ作成）
 	var tagIDs []int64
 	for _, tagName := range frontMatter.Tags {
-		t, err := s.findOrCreateTag(tagName)
+		t, err := s.findOrCreateTag(actor, tagName)
 		if err != nil {
 			return nil, fmt.Errorf("タグ解決エラー: %w", err)
 		}
 		tagIDs = append(tagIDs, t.ID)
 	}
 
-	// 記事作成（authorID=1をデフォルトとする）
+	// 記事作成（作成者はインポートするユーザー）
 	article, err := s.articleService.Create(
+		actor,
 		frontMatter.Title,
 		frontMatter.Slug,
 		body,
 		frontMatter.Status,
-		1, // authorID (デフォルト)
+		actor.ID,
 		categoryID,
 		tagIDs,
+		frontMatter.PublishedAt, // 未指定で published なら現在時刻
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	// statusが"published"の場合は、published_atを設定するためにPublishを呼ぶ
-	if frontMatter.Status == "published" {
-		return s.articleService.Publish(article.ID)
+	// 元の日付があれば作成日時・更新日時にも反映する
+	if frontMatter.PublishedAt != nil || frontMatter.UpdatedAt != nil {
+		createdAt, updatedAt := importTimestamps(frontMatter.PublishedAt, frontMatter.UpdatedAt)
+		return s.articleService.SetTimestamps(actor, article.ID, createdAt, updatedAt)
 	}
 
 	return article, nil
 }
 
+// importTimestamps はフロントマターの日付から作成日時・更新日時を決める
+// （作成日時は公開日時、更新日時は指定がなければ公開日時）
+func importTimestamps(publishedAt, updatedAt *time.Time) (time.Time, time.Time) {
+	if publishedAt == nil {
+		return *updatedAt, *updatedAt
+	}
+	if updatedAt == nil {
+		return *publishedAt, *publishedAt
+	}
+	return *publishedAt, *updatedAt
+}
+
 // parseFrontMatter はフロントマターと本文を分離
 func parseFrontMatter(content string) (*FrontMatter, string, error) {
 	scanner := bufio.NewScanner(strings.NewReader(content))
@@ -143,7 +163,7 @@ func parseFrontMatter(content string) (*FrontMatter, string, error) {
 }
 
 // findOrCreateCategory はカテゴリを名前で検索し、なければ作成
-func (s *Service) findOrCreateCategory(name string) (*category.Category, error) {
+func (s *Service) findOrCreateCategory(actor *user.User, name string) (*category.Category, error) {
 	categories, err := s.categoryRepo.GetAll()
 	if err != nil {
 		return nil, err
@@ -156,13 +176,16 @@ func (s *Service) findOrCreateCategory(name string) (*category.Category, error)
 		}
 	}
 
-	// なければ作成
+	// なければ作成（カテゴリの作成は編集者以上のみ）
+	if err := user.RequireEditor(actor); err != nil {
+		return nil, err
+	}
 	slug := generateSlug(name)
 	return s.categoryRepo.Create(name, slug)
 }
 
 // findOrCreateTag はタグを名前で検索し、なければ作成
-func (s *Service) findOrCreateTag(name string) (*tag.Tag, error) {
+func (s *Service) findOrCreateTag(actor *user.User, name string) (*tag.Tag, error) {
 	tags, err := s.tagRepo.GetAll()
 	if err != nil {
 		return nil, err
@@ -175,7 +198,10 @@ func (s *Service) findOrCreateTag(name string) (*tag.Tag, error) {
 		}
 	}
 
-	// なければ作成
+	// なければ作成（タグの作成は編集者以上のみ）
+	if err := user.RequireEditor(actor); err != nil {
+		return nil, err
+	}
 	slug := generateSlug(name)
 	return s.tagRepo.Create(name, slug)
 }
@@ -0,0 +1,22 @@
+package markdown
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Extensions は設定で有効・無効を切り替えられる Markdown の拡張
+// （設定の検証とエクスポートの描画の両方がこの一覧を使う）
+var Extensions = map[string]goldmark.Extender{
+	"table":         extension.Table,
+	"strikethrough": extension.Strikethrough,
+	"tasklist":      extension.TaskList,
+	"linkify":       extension.Linkify,
+	"footnote":      extension.Footnote,
+}
+
+// ValidExtension は markdown_extensions に指定できる拡張の名前かどうか
+func ValidExtension(name string) bool {
+	_, ok := Extensions[name]
+	return ok
+}
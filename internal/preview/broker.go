@@ -0,0 +1,40 @@
+package preview
+
+import "sync"
+
+// Broker はプレビューを開いているブラウザに再読み込みを通知する
+type Broker struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{clients: map[chan struct{}]struct{}{}}
+}
+
+// Subscribe は通知を受け取るチャネルを登録
+func (b *Broker) Subscribe() chan struct{} {
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	b.clients[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *Broker) Unsubscribe(ch chan struct{}) {
+	b.mu.Lock()
+	delete(b.clients, ch)
+	b.mu.Unlock()
+}
+
+// Notify は全クライアントに再読み込みを通知（未処理の通知があればまとめる）
+func (b *Broker) Notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package preview
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cms/internal/export"
+	"cms/internal/settings"
+	"cms/internal/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventsPath はライブリロード用の SSE エンドポイント（/preview/ 以下）
+const eventsPath = "/__livereload"
+
+// liveReloadScript はプレビューのHTMLに挿入するライブリロード用のスクリプト
+const liveReloadScript = `<script>
+(function () {
+  var es = new EventSource("/preview` + eventsPath + `");
+  es.addEventListener("reload", function () { location.reload(); });
+})();
+</script>
+`
+
+// watchedPrefixes は変更されたらプレビューを再読み込みさせる API
+var watchedPrefixes = []string{
+	"/api/articles",
+	"/api/categories",
+	"/api/tags",
+	"/api/templates",
+	"/api/settings",
+	"/api/images",
+	"/api/trash",
+	"/api/users",
+}
+
+type Handler struct {
+	service         *export.Service
+	settingsService *settings.Service
+	broker          *Broker
+	includeDrafts   bool
+}
+
+func NewHandler(db *sql.DB, includeDrafts bool) *Handler {
+	return &Handler{
+		service:         export.NewService(db),
+		settingsService: settings.NewService(),
+		broker:          NewBroker(),
+		includeDrafts:   includeDrafts,
+	}
+}
+
+// RegisterRoutes は /preview 以下のルートを登録する（r にはログイン中のユーザーを特定するミドルウェアを付けておく）
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/preview", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/preview/")
+	})
+	r.GET("/preview/*path", h.Serve)
+}
+
+// NotifyOnChange は記事・テンプレート・設定などを変更する API が成功したら
+// プレビューを開いているブラウザに再読み込みを通知するミドルウェア
+func (h *Handler) NotifyOnChange() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		for _, prefix := range watchedPrefixes {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				h.broker.Notify()
+				return
+			}
+		}
+	}
+}
+
+// Serve はエクスポートと同じレイアウト（/preview/posts/{slug}.html など）でサイトを描画して返す。
+// 下書きも表示する場合はログインが必要
+func (h *Handler) Serve(c *gin.Context) {
+	if _, ok := user.FromContext(c); h.includeDrafts && !ok {
+		c.String(http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	p := c.Param("path")
+	if p == eventsPath {
+		h.events(c)
+		return
+	}
+
+	s, err := h.settingsService.Get()
+	if err != nil {
+		c.String(http.StatusInternalServerError, "failed to load settings: "+err.Error())
+		return
+	}
+	cfg := export.NewConfig(s)
+
+	// ディレクトリは index.html を返す
+	rel := strings.TrimPrefix(path.Clean(p), "/")
+	if rel == "" || strings.HasSuffix(p, "/") {
+		rel = path.Join(rel, "index.html")
+	}
+
+	// 画像はアップロードディレクトリから返す
+	assetPrefix := strings.Trim(s.AssetPath, "/") + "/"
+	if strings.HasPrefix(rel, assetPrefix) {
+		name := strings.TrimPrefix(rel, assetPrefix)
+		if strings.Contains(name, "/") {
+			c.String(http.StatusNotFound, "not found")
+			return
+		}
+		c.File(filepath.Join(cfg.UploadDir, name))
+		return
+	}
+
+	data, err := h.service.Render(cfg, rel, h.includeDrafts)
+	if err != nil {
+		if errors.Is(err, export.ErrNotFound) {
+			c.String(http.StatusNotFound, "not found: /"+rel)
+			return
+		}
+		// テンプレートのエラーなどはそのまま表示する
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(rel))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if strings.HasPrefix(contentType, "text/html") {
+		data = injectLiveReload(data)
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// events は再読み込みの通知を SSE で送る
+func (h *Handler) events(c *gin.Context) {
+	ch := h.broker.Subscribe()
+	defer h.broker.Unsubscribe(ch)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ch:
+			c.SSEvent("reload", "")
+			return true
+		case <-time.After(30 * time.Second):
+			// 接続を維持するためのコメント
+			io.WriteString(w, ": ping\n\n")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// injectLiveReload は </body> の直前にライブリロード用のスクリプトを挿入
+func injectLiveReload(html []byte) []byte {
+	i := bytes.LastIndex(html, []byte("</body>"))
+	if i < 0 {
+		return append(html, liveReloadScript...)
+	}
+
+	out := make([]byte, 0, len(html)+len(liveReloadScript))
+	out = append(out, html[:i]...)
+	out = append(out, liveReloadScript...)
+	return append(out, html[i:]...)
+}
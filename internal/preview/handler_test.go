@@ -0,0 +1,36 @@
+package preview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServeRequiresLoginForDrafts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	(&Handler{includeDrafts: true, broker: NewBroker()}).RegisterRoutes(r)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/preview", want: http.StatusMovedPermanently},
+		{path: "/preview/", want: http.StatusUnauthorized},
+		{path: "/preview/posts/draft.html", want: http.StatusUnauthorized},
+		{path: "/preview/images/a.png", want: http.StatusUnauthorized},
+		{path: "/preview/__livereload", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package publisher
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"cms/internal/article"
+	"cms/internal/export"
+	"cms/internal/settings"
+	"cms/internal/user"
+)
+
+// Publisher は公開日時を過ぎた予約投稿を公開する
+type Publisher struct {
+	articleService  *article.Service
+	exportService   *export.Service
+	settingsService *settings.Service
+
+	// 公開した記事があればエクスポートする
+	exportOnPublish bool
+}
+
+func New(db *sql.DB, exportOnPublish bool) *Publisher {
+	return &Publisher{
+		articleService:  article.NewService(db),
+		exportService:   export.NewService(db),
+		settingsService: settings.NewService(),
+		exportOnPublish: exportOnPublish,
+	}
+}
+
+// PublishDue は予約投稿を公開し、公開した記事の ID を返す
+func (p *Publisher) PublishDue() ([]int64, error) {
+	ids, err := p.articleService.PublishDue()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 || !p.exportOnPublish {
+		return ids, nil
+	}
+
+	s, err := p.settingsService.Get()
+	if err != nil {
+		return ids, err
+	}
+	if _, err := p.exportService.Export(user.System, export.NewConfig(s)); err != nil {
+		return ids, err
+	}
+	return ids, nil
+}
+
+// Run は interval ごとに PublishDue を実行する（ctx が終了するまで）
+func (p *Publisher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.run()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Publisher) run() {
+	ids, err := p.PublishDue()
+	if len(ids) > 0 {
+		log.Printf("Published scheduled articles: %v", ids)
+	}
+	if err != nil {
+		log.Println("Failed to publish scheduled articles:", err)
+	}
+}
@@ -1,8 +1,13 @@
 package settings
 
 import (
+	"errors"
 	"net/http"
 
+	"cms/internal/auth"
+	"cms/internal/etag"
+	"cms/internal/user"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -26,15 +31,52 @@ func (h *Handler) Get(c *gin.Context) {
 		return
 	}
 
+	if !h.setETag(c, settings) {
+		return
+	}
 	c.JSON(http.StatusOK, settings)
 }
 
+// setETag は設定の ETag をヘッダに設定する
+func (h *Handler) setETag(c *gin.Context, settings *Settings) bool {
+	tag, err := h.service.ETag(settings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	etag.Set(c, tag)
+	return true
+}
+
 type UpdateRequest struct {
 	ExportDir string `json:"export_dir" binding:"required"`
 	SiteTitle string `json:"site_title"`
+	BaseURL   string `json:"base_url"`
+	AssetPath string `json:"asset_path"`
+	RobotsTxt string `json:"robots_txt"`
+	PageSize  int    `json:"page_size"`
+
+	HighlightStyle       string `json:"highlight_style"`
+	HighlightLineNumbers bool   `json:"highlight_line_numbers"`
+
+	// 有効にする Markdown の拡張（未設定ならすべて有効）
+	MarkdownExtensions []string `json:"markdown_extensions"`
+
+	// 記事ごとに保持するリビジョンの数（未指定なら既定値、0は無制限）
+	RevisionRetention *int `json:"revision_retention"`
 }
 
 func (h *Handler) Update(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	ifMatch, ok := etag.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -44,12 +86,58 @@ func (h *Handler) Update(c *gin.Context) {
 	settings := &Settings{
 		ExportDir: req.ExportDir,
 		SiteTitle: req.SiteTitle,
+		BaseURL:   req.BaseURL,
+		AssetPath: req.AssetPath,
+		RobotsTxt: req.RobotsTxt,
+		PageSize:  req.PageSize,
+
+		HighlightStyle:       req.HighlightStyle,
+		HighlightLineNumbers: req.HighlightLineNumbers,
+
+		MarkdownExtensions: req.MarkdownExtensions,
+
+		RevisionRetention: defaultSettings.RevisionRetention,
+	}
+	if req.RevisionRetention != nil {
+		settings.RevisionRetention = *req.RevisionRetention
 	}
 
-	if err := h.service.Update(settings); err != nil {
+	if err := h.service.UpdateIfMatch(u, settings, ifMatch); err != nil {
+		if errors.Is(err, etag.ErrMismatch) {
+			h.preconditionFailed(c)
+			return
+		}
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, settings)
+	// 保存した内容を読み直して返す（ETag を GET と揃えるため）
+	saved, err := h.service.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.setETag(c, saved) {
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+// preconditionFailed は 412 と現在の設定を返す
+func (h *Handler) preconditionFailed(c *gin.Context) {
+	current, err := h.service.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	tag, err := h.service.ETag(current)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	etag.PreconditionFailed(c, tag, current)
 }
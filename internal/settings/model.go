@@ -3,4 +3,17 @@ package settings
 type Settings struct {
 	ExportDir string `json:"export_dir"`
 	SiteTitle string `json:"site_title"`
+	BaseURL   string `json:"base_url"`
+	AssetPath string `json:"asset_path"`
+	RobotsTxt string `json:"robots_txt"`
+	PageSize  int    `json:"page_size"`
+
+	HighlightStyle       string `json:"highlight_style"`
+	HighlightLineNumbers bool   `json:"highlight_line_numbers"`
+
+	// 有効にする Markdown の拡張（未設定ならすべて有効）
+	MarkdownExtensions []string `json:"markdown_extensions"`
+
+	// 記事ごとに保持するリビジョンの数（0は無制限）
+	RevisionRetention int `json:"revision_retention"`
 }
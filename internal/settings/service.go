@@ -3,8 +3,18 @@ package settings
 import (
 	"encoding/json"
 	"errors"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"sync"
+
+	"cms/internal/etag"
+	"cms/internal/markdown"
+	"cms/internal/user"
+
+	"github.com/alecthomas/chroma/v2/styles"
 )
 
 const configFile = "config.json"
@@ -12,8 +22,16 @@ const configFile = "config.json"
 var defaultSettings = Settings{
 	ExportDir: "/tmp/cms-export",
 	SiteTitle: "Blog",
+	AssetPath: "images",
+
+	HighlightStyle: "github-dark",
+
+	RevisionRetention: 50,
 }
 
+// updateMu は設定ファイルの ETag の確認と書き込みの間に他の更新が入らないようにする
+var updateMu sync.Mutex
+
 type Service struct{}
 
 func NewService() *Service {
@@ -30,7 +48,8 @@ func (s *Service) Get() (*Settings, error) {
 		return nil, err
 	}
 
-	var settings Settings
+	// ファイルにない項目はデフォルト値のまま
+	settings := defaultSettings
 	if err := json.Unmarshal(data, &settings); err != nil {
 		return nil, err
 	}
@@ -39,16 +58,108 @@ func (s *Service) Get() (*Settings, error) {
 	if settings.SiteTitle == "" {
 		settings.SiteTitle = defaultSettings.SiteTitle
 	}
+	if settings.AssetPath == "" {
+		settings.AssetPath = defaultSettings.AssetPath
+	}
+	if settings.HighlightStyle == "" {
+		settings.HighlightStyle = defaultSettings.HighlightStyle
+	}
 
 	return &settings, nil
 }
 
-func (s *Service) Update(settings *Settings) error {
+// ETag は設定の内容から ETag を作る
+func (s *Service) ETag(settings *Settings) (string, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+	return etag.Hash(data), nil
+}
+
+// UpdateIfMatch は現在の設定の ETag が ifMatch と一致するときだけ更新する（違えば etag.ErrMismatch）。
+// ifMatch が * なら確認しない。管理者のみ
+func (s *Service) UpdateIfMatch(actor *user.User, settings *Settings, ifMatch string) error {
+	if err := user.RequireAdmin(actor); err != nil {
+		return err
+	}
+
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
+	if ifMatch != etag.Any {
+		current, err := s.Get()
+		if err != nil {
+			return err
+		}
+		tag, err := s.ETag(current)
+		if err != nil {
+			return err
+		}
+		if tag != ifMatch {
+			return etag.ErrMismatch
+		}
+	}
+	return s.update(settings)
+}
+
+// Update は設定を保存する。管理者のみ
+func (s *Service) Update(actor *user.User, settings *Settings) error {
+	if err := user.RequireAdmin(actor); err != nil {
+		return err
+	}
+
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
+	return s.update(settings)
+}
+
+func (s *Service) update(settings *Settings) error {
 	// パスの検証
 	if err := s.validateExportDir(settings.ExportDir); err != nil {
 		return err
 	}
 
+	// ベースURLの検証（末尾のスラッシュは除去して保存）
+	settings.BaseURL = strings.TrimSuffix(settings.BaseURL, "/")
+	if err := s.validateBaseURL(settings.BaseURL); err != nil {
+		return err
+	}
+
+	// 画像の出力先の検証
+	if settings.AssetPath == "" {
+		settings.AssetPath = defaultSettings.AssetPath
+	}
+	if err := s.validateAssetPath(settings.AssetPath); err != nil {
+		return err
+	}
+
+	// コードブロックのハイライトのスタイル
+	if settings.HighlightStyle == "" {
+		settings.HighlightStyle = defaultSettings.HighlightStyle
+	}
+	if _, ok := styles.Registry[settings.HighlightStyle]; !ok {
+		return errors.New("unknown highlight_style: " + settings.HighlightStyle)
+	}
+
+	// Markdown の拡張
+	for _, name := range settings.MarkdownExtensions {
+		if !markdown.ValidExtension(name) {
+			return errors.New("unknown markdown extension: " + name)
+		}
+	}
+
+	// 一覧の1ページあたりの記事数（0は分割しない）
+	if settings.PageSize < 0 {
+		return errors.New("page_size must be 0 or greater")
+	}
+
+	// リビジョンの保持数（0は無制限）
+	if settings.RevisionRetention < 0 {
+		return errors.New("revision_retention must be 0 or greater")
+	}
+
 	// JSONに変換
 	data, err := json.MarshalIndent(settings, "", "  ")
 	if err != nil {
@@ -84,3 +195,48 @@ func (s *Service) validateExportDir(dir string) error {
 
 	return nil
 }
+
+// validateBaseURL はフィード等で使う絶対URLを検証（空の場合は未設定扱い）
+func (s *Service) validateBaseURL(baseURL string) error {
+	if baseURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return errors.New("invalid base_url: " + err.Error())
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("base_url must be an absolute http(s) URL")
+	}
+
+	return nil
+}
+
+// reservedPaths はエクスポートが生成するため画像の出力先に使えないパス
+var reservedPaths = map[string]bool{
+	"posts":      true,
+	"categories": true,
+	"tags":       true,
+	"page":       true,
+	"archive":    true,
+	"css":        true,
+}
+
+// validateAssetPath は画像の出力先（エクスポート先からの相対パス）を検証
+func (s *Service) validateAssetPath(assetPath string) error {
+	cleaned := path.Clean(assetPath)
+	if path.IsAbs(assetPath) || cleaned != assetPath || cleaned == "." || strings.HasPrefix(cleaned, "..") {
+		return errors.New("asset_path must be a clean relative path (e.g. images)")
+	}
+	if strings.HasPrefix(cleaned, ".") {
+		return errors.New("asset_path must not be a hidden directory")
+	}
+
+	top := strings.SplitN(cleaned, "/", 2)[0]
+	if reservedPaths[top] {
+		return errors.New("asset_path must not be inside " + top + "/")
+	}
+
+	return nil
+}
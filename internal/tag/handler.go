@@ -2,9 +2,14 @@ package tag
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"cms/internal/auth"
+	"cms/internal/etag"
+	"cms/internal/user"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -54,53 +59,122 @@ func (h *Handler) GetByID(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	etag.Set(c, etag.Version(tag.Version))
 	c.JSON(http.StatusOK, tag)
 }
 
 func (h *Handler) Create(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	var req CreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	tag, err := h.service.Create(req.Name, req.Slug)
+	tag, err := h.service.Create(u, req.Name, req.Slug)
 	if err != nil {
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrSlugTaken) || errors.Is(err, ErrSlugInTrash) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	etag.Set(c, etag.Version(tag.Version))
 	c.JSON(http.StatusCreated, tag)
 }
 
 func (h *Handler) Update(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
+	version, ok := etag.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req CreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	tag, err := h.service.Update(id, req.Name, req.Slug)
+	tag, err := h.service.Update(u, id, req.Name, req.Slug, version)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+			return
+		}
+		if errors.Is(err, etag.ErrMismatch) {
+			h.preconditionFailed(c, id)
+			return
+		}
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrSlugTaken) || errors.Is(err, ErrSlugInTrash) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	etag.Set(c, etag.Version(tag.Version))
 	c.JSON(http.StatusOK, tag)
 }
 
+// preconditionFailed は 412 と現在のタグを返す
+func (h *Handler) preconditionFailed(c *gin.Context, id int64) {
+	current, err := h.service.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	etag.PreconditionFailed(c, etag.Version(current.Version), current)
+}
+
 func (h *Handler) Delete(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(u, id); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+			return
+		}
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
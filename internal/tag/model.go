@@ -1,10 +1,22 @@
 package tag
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrSlugTaken   = errors.New("name or slug is already in use")
+	ErrSlugInTrash = errors.New("name or slug is used by a tag in the trash (restore or purge it first)")
+)
 
 type Tag struct {
 	ID        int64     `json:"id"`
 	Name      string    `json:"name"`
 	Slug      string    `json:"slug"`
 	CreatedAt time.Time `json:"created_at"`
+	// 更新のたびに増えるバージョン（ETag）
+	Version int64 `json:"version,omitempty"`
+	// ゴミ箱に入れた日時（ゴミ箱の一覧でのみ返す）
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
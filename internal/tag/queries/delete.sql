@@ -1,2 +1,3 @@
-DELETE FROM tags WHERE id = ?
-
+UPDATE tags 
+SET deleted_at = ? 
+WHERE id = ? AND deleted_at IS NULL
@@ -1,4 +1,3 @@
-SELECT id, name, slug, created_at 
+SELECT id, name, slug, created_at, version 
 FROM tags 
-WHERE id = ?
-
+WHERE id = ? AND deleted_at IS NULL
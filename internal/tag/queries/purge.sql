@@ -0,0 +1 @@
+DELETE FROM tags WHERE id = ? AND deleted_at IS NOT NULL
@@ -0,0 +1 @@
+SELECT EXISTS (SELECT 1 FROM tags WHERE (name = ? OR slug = ?) AND deleted_at IS NOT NULL)
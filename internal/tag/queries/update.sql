@@ -1,4 +1,3 @@
 UPDATE tags 
-SET name = ?, slug = ? 
-WHERE id = ?
-
+SET name = ?, slug = ?, version = version + 1
+WHERE id = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)
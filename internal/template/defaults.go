@@ -17,6 +17,12 @@ var defaultCategory string
 //go:embed defaults/tag.html
 var defaultTag string
 
+//go:embed defaults/archive.html
+var defaultArchive string
+
+//go:embed defaults/search.html
+var defaultSearch string
+
 // DefaultTemplates はデフォルトテンプレートのマップ
 var DefaultTemplates = map[string]string{
 	TemplateBase:     defaultBase,
@@ -24,5 +30,7 @@ var DefaultTemplates = map[string]string{
 	TemplateIndex:    defaultIndex,
 	TemplateCategory: defaultCategory,
 	TemplateTag:      defaultTag,
+	TemplateArchive:  defaultArchive,
+	TemplateSearch:   defaultSearch,
 }
 
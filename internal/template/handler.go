@@ -3,12 +3,17 @@ package template
 import (
 	"archive/zip"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"path/filepath"
 	"strings"
 
+	"cms/internal/auth"
+	"cms/internal/etag"
+	"cms/internal/user"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -50,6 +55,7 @@ func (h *Handler) GetByName(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	etag.Set(c, etag.Version(template.Version))
 	c.JSON(http.StatusOK, template)
 }
 
@@ -58,28 +64,66 @@ type UpdateRequest struct {
 }
 
 func (h *Handler) Update(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	name := c.Param("name")
 
+	version, ok := etag.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	template, err := h.service.Update(name, req.Content)
+	template, err := h.service.UpdateIfMatch(u, name, req.Content, version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusNotFound, gin.H{"error": "invalid template name"})
 			return
 		}
+		if errors.Is(err, etag.ErrMismatch) {
+			h.preconditionFailed(c, name)
+			return
+		}
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	etag.Set(c, etag.Version(template.Version))
 	c.JSON(http.StatusOK, template)
 }
 
+// preconditionFailed は 412 と現在のテンプレートを返す
+func (h *Handler) preconditionFailed(c *gin.Context, name string) {
+	current, err := h.service.GetByName(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	etag.PreconditionFailed(c, etag.Version(current.Version), current)
+}
+
 func (h *Handler) Reset(c *gin.Context) {
-	if err := h.service.ResetToDefaults(); err != nil {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.ResetToDefaults(u); err != nil {
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -88,6 +132,11 @@ func (h *Handler) Reset(c *gin.Context) {
 
 // Upload は単一テンプレートファイルをアップロード
 func (h *Handler) Upload(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	name := c.Param("name")
 
 	// テンプレート名のバリデーション
@@ -123,8 +172,12 @@ func (h *Handler) Upload(c *gin.Context) {
 	}
 
 	// DB に保存
-	template, err := h.service.Update(name, string(content))
+	template, err := h.service.Update(u, name, string(content))
 	if err != nil {
+		if errors.Is(err, user.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -134,6 +187,11 @@ func (h *Handler) Upload(c *gin.Context) {
 
 // Import はZIPファイルから複数テンプレートを一括インポート
 func (h *Handler) Import(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
 	file, err := c.FormFile("file")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "zip file is required"})
@@ -195,7 +253,11 @@ func (h *Handler) Import(c *gin.Context) {
 		}
 
 		// DB に保存
-		_, err = h.service.Update(templateName, string(content))
+		_, err = h.service.Update(u, templateName, string(content))
+		if err == user.ErrForbidden {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %s", templateName, err.Error()))
 			continue
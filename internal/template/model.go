@@ -8,6 +8,8 @@ type Template struct {
 	Content   string    `json:"content"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// 更新のたびに増えるバージョン（ETag）
+	Version int64 `json:"version"`
 }
 
 // テンプレート名の定数
@@ -17,6 +19,8 @@ const (
 	TemplateIndex    = "index"
 	TemplateCategory = "category"
 	TemplateTag      = "tag"
+	TemplateArchive  = "archive"
+	TemplateSearch   = "search"
 )
 
 // AllTemplateNames は全テンプレート名のリスト
@@ -26,5 +30,7 @@ var AllTemplateNames = []string{
 	TemplateIndex,
 	TemplateCategory,
 	TemplateTag,
+	TemplateArchive,
+	TemplateSearch,
 }
 
@@ -11,3 +11,6 @@ var queryGetByName string
 //go:embed queries/upsert.sql
 var queryUpsert string
 
+//go:embed queries/update.sql
+var queryUpdate string
+
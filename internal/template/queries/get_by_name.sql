@@ -1,4 +1,4 @@
-SELECT id, name, content, created_at, updated_at
+SELECT id, name, content, created_at, updated_at, version
 FROM templates
 WHERE name = ?
 
@@ -0,0 +1,3 @@
+UPDATE templates
+SET content = ?, updated_at = ?, version = version + 1
+WHERE name = ? AND version = ?
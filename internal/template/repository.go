@@ -23,7 +23,7 @@ func (r *Repository) GetAll() ([]Template, error) {
 	var templates []Template
 	for rows.Next() {
 		var t Template
-		err := rows.Scan(&t.ID, &t.Name, &t.Content, &t.CreatedAt, &t.UpdatedAt)
+		err := rows.Scan(&t.ID, &t.Name, &t.Content, &t.CreatedAt, &t.UpdatedAt, &t.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -35,7 +35,7 @@ func (r *Repository) GetAll() ([]Template, error) {
 func (r *Repository) GetByName(name string) (*Template, error) {
 	var t Template
 	err := r.db.QueryRow(queryGetByName, name).Scan(
-		&t.ID, &t.Name, &t.Content, &t.CreatedAt, &t.UpdatedAt,
+		&t.ID, &t.Name, &t.Content, &t.CreatedAt, &t.UpdatedAt, &t.Version,
 	)
 	if err != nil {
 		return nil, err
@@ -52,9 +52,18 @@ func (r *Repository) Upsert(name, content string) (*Template, error) {
 	return r.GetByName(name)
 }
 
-func (r *Repository) Count() (int, error) {
-	var count int
-	err := r.db.QueryRow("SELECT COUNT(*) FROM templates").Scan(&count)
-	return count, err
+// Update はテンプレートがそのバージョンのときだけ更新する（一致しなければ sql.ErrNoRows）
+func (r *Repository) Update(name, content string, version int64) (*Template, error) {
+	result, err := r.db.Exec(queryUpdate, content, time.Now(), name, version)
+	if err != nil {
+		return nil, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return r.GetByName(name)
 }
-
@@ -2,6 +2,9 @@ package template
 
 import (
 	"database/sql"
+
+	"cms/internal/etag"
+	"cms/internal/user"
 )
 
 type Service struct {
@@ -20,7 +23,12 @@ func (s *Service) GetByName(name string) (*Template, error) {
 	return s.repo.GetByName(name)
 }
 
-func (s *Service) Update(name, content string) (*Template, error) {
+// Update はテンプレートを更新する。管理者のみ
+func (s *Service) Update(actor *user.User, name, content string) (*Template, error) {
+	if err := user.RequireAdmin(actor); err != nil {
+		return nil, err
+	}
+
 	// バリデーション: 有効なテンプレート名かチェック
 	valid := false
 	for _, n := range AllTemplateNames {
@@ -36,7 +44,37 @@ func (s *Service) Update(name, content string) (*Template, error) {
 	return s.repo.Upsert(name, content)
 }
 
-func (s *Service) ResetToDefaults() error {
+// UpdateIfMatch はテンプレートがそのバージョンのときだけ更新する（違えば etag.ErrMismatch）。
+// version が 0 なら Update と同じ。管理者のみ
+func (s *Service) UpdateIfMatch(actor *user.User, name, content string, version int64) (*Template, error) {
+	if version == 0 {
+		return s.Update(actor, name, content)
+	}
+	if err := user.RequireAdmin(actor); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if current.Version != version {
+		return nil, etag.ErrMismatch
+	}
+
+	template, err := s.repo.Update(name, content, version)
+	if err == sql.ErrNoRows {
+		// 取得してから更新するまでの間に他で更新された
+		return nil, etag.ErrMismatch
+	}
+	return template, err
+}
+
+// ResetToDefaults はすべてのテンプレートをデフォルトに戻す。管理者のみ
+func (s *Service) ResetToDefaults(actor *user.User) error {
+	if err := user.RequireAdmin(actor); err != nil {
+		return err
+	}
 	for _, name := range AllTemplateNames {
 		content, ok := DefaultTemplates[name]
 		if !ok {
@@ -49,14 +87,23 @@ func (s *Service) ResetToDefaults() error {
 	return nil
 }
 
-// InitializeDefaults はDBにテンプレートがなければデフォルトを投入
+// InitializeDefaults はDBにないテンプレートだけデフォルトを投入（編集済みのものは変更しない）
 func (s *Service) InitializeDefaults() error {
-	count, err := s.repo.Count()
-	if err != nil {
-		return err
-	}
-	if count == 0 {
-		return s.ResetToDefaults()
+	for _, name := range AllTemplateNames {
+		content, ok := DefaultTemplates[name]
+		if !ok {
+			continue
+		}
+		_, err := s.repo.GetByName(name)
+		if err == nil {
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+		if _, err := s.repo.Upsert(name, content); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -0,0 +1,86 @@
+package trash
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"cms/internal/auth"
+	"cms/internal/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(db *sql.DB) *Handler {
+	return &Handler{service: NewService(db)}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/trash", h.Get)
+	r.POST("/trash/:type/:id/restore", h.Restore)
+	r.DELETE("/trash/:type/:id", h.Purge)
+}
+
+func (h *Handler) Get(c *gin.Context) {
+	trash, err := h.service.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, trash)
+}
+
+func (h *Handler) Restore(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.service.Restore(u, c.Param("type"), id); err != nil {
+		h.error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "restored"})
+}
+
+func (h *Handler) Purge(c *gin.Context) {
+	u, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.service.Purge(u, c.Param("type"), id); err != nil {
+		h.error(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Handler) error(c *gin.Context, err error) {
+	switch err {
+	case ErrUnknownType:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case sql.ErrNoRows:
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found in trash"})
+	case user.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,93 @@
+package trash
+
+import (
+	"database/sql"
+	"errors"
+
+	"cms/internal/article"
+	"cms/internal/category"
+	"cms/internal/tag"
+	"cms/internal/user"
+)
+
+// ErrUnknownType はゴミ箱の種類（articles, categories, tags）が不正
+var ErrUnknownType = errors.New("type must be articles, categories or tags")
+
+// Trash はゴミ箱の中身
+type Trash struct {
+	Articles   []article.ArticleSummary `json:"articles"`
+	Categories []category.Category      `json:"categories"`
+	Tags       []tag.Tag                `json:"tags"`
+}
+
+// trashable はゴミ箱から戻す・完全に削除できるもの
+type trashable interface {
+	Restore(actor *user.User, id int64) error
+	Purge(actor *user.User, id int64) error
+}
+
+type Service struct {
+	articleService  *article.Service
+	categoryService *category.Service
+	tagService      *tag.Service
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{
+		articleService:  article.NewService(db),
+		categoryService: category.NewService(db),
+		tagService:      tag.NewService(db),
+	}
+}
+
+func (s *Service) Get() (*Trash, error) {
+	articles, err := s.articleService.GetTrashed()
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryService.GetTrashed()
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagService.GetTrashed()
+	if err != nil {
+		return nil, err
+	}
+
+	// 一覧では本文を省略する
+	summaries := make([]article.ArticleSummary, len(articles))
+	for i, a := range articles {
+		summaries[i] = article.ArticleSummary{Article: a}
+	}
+	return &Trash{Articles: summaries, Categories: categories, Tags: tags}, nil
+}
+
+// Restore はゴミ箱から戻す（見つからなければ sql.ErrNoRows）
+func (s *Service) Restore(actor *user.User, kind string, id int64) error {
+	t, err := s.target(kind)
+	if err != nil {
+		return err
+	}
+	return t.Restore(actor, id)
+}
+
+// Purge はゴミ箱から完全に削除する（見つからなければ sql.ErrNoRows）
+func (s *Service) Purge(actor *user.User, kind string, id int64) error {
+	t, err := s.target(kind)
+	if err != nil {
+		return err
+	}
+	return t.Purge(actor, id)
+}
+
+func (s *Service) target(kind string) (trashable, error) {
+	switch kind {
+	case "articles":
+		return s.articleService, nil
+	case "categories":
+		return s.categoryService, nil
+	case "tags":
+		return s.tagService, nil
+	}
+	return nil, ErrUnknownType
+}
@@ -0,0 +1,21 @@
+package user
+
+import "github.com/gin-gonic/gin"
+
+// contextKey はリクエストのコンテキストにログイン中のユーザーを入れるキー
+const contextKey = "auth.user"
+
+// SetContext はログイン中のユーザーをリクエストのコンテキストに入れる
+func SetContext(c *gin.Context, u *User) {
+	c.Set(contextKey, u)
+}
+
+// FromContext はリクエストのコンテキストからログイン中のユーザーを返す
+func FromContext(c *gin.Context) (*User, bool) {
+	v, ok := c.Get(contextKey)
+	if !ok {
+		return nil, false
+	}
+	u, ok := v.(*User)
+	return u, ok
+}
@@ -0,0 +1,194 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(db *sql.DB) *Handler {
+	return &Handler{service: NewService(db)}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/users", h.GetAll)
+	r.GET("/users/:id", h.GetByID)
+	r.POST("/users", h.Create)
+	r.PUT("/users/:id", h.Update)
+	r.PUT("/users/:id/password", h.SetPassword)
+	r.DELETE("/users/:id", h.Delete)
+}
+
+type CreateRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role"`
+}
+
+type UpdateRequest struct {
+	Email string `json:"email" binding:"required"`
+	Name  string `json:"name" binding:"required"`
+	Role  string `json:"role"`
+}
+
+type PasswordRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+func (h *Handler) GetAll(c *gin.Context) {
+	actor, ok := requireActor(c)
+	if !ok {
+		return
+	}
+
+	users, err := h.service.GetAll(actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+func (h *Handler) GetByID(c *gin.Context) {
+	actor, ok := requireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	user, err := h.service.GetByID(actor, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	actor, ok := requireActor(c)
+	if !ok {
+		return
+	}
+
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.service.Create(actor, req.Email, req.Name, req.Password, req.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, user)
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	actor, ok := requireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.service.Update(actor, id, req.Email, req.Name, req.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *Handler) SetPassword(c *gin.Context) {
+	actor, ok := requireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req PasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetPassword(actor, id, req.Password); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	actor, ok := requireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.service.Delete(actor, id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// respondError はサービスのエラーをステータスコードに対応させて返す
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case errors.Is(err, ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrEmailTaken), errors.Is(err, ErrHasArticles), errors.Is(err, ErrLastAdmin):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrInvalidEmail), errors.Is(err, ErrPasswordTooShort), errors.Is(err, ErrPasswordTooLong), errors.Is(err, ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// requireActor はログイン中のユーザーを返す。ログインしていなければ 401 を返して false
+func requireActor(c *gin.Context) (*User, bool) {
+	u, ok := FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return nil, false
+	}
+	return u, true
+}
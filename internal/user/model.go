@@ -1,12 +1,74 @@
 package user
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ユーザーの権限
+const (
+	RoleAdmin  = "admin"  // 設定・テンプレート・ユーザーの管理を含むすべての操作
+	RoleEditor = "editor" // すべての記事の編集・公開、カテゴリ・タグの管理、エクスポート
+	RoleAuthor = "author" // 自分の下書きの作成・編集とレビュー依頼
+)
+
+var (
+	ErrEmailTaken       = errors.New("email is already in use")
+	ErrInvalidEmail     = errors.New("invalid email")
+	ErrPasswordTooShort = errors.New("password must be at least 8 characters")
+	ErrPasswordTooLong  = errors.New("password must be at most 72 bytes")
+	ErrHasArticles      = errors.New("user has articles")
+	ErrInvalidRole      = errors.New("role must be admin, editor or author")
+	ErrLastAdmin        = errors.New("cannot remove the last admin")
+
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrForbidden          = errors.New("permission denied")
+)
+
+// System は CLI のユーザー管理や予約投稿の公開など、ユーザーの操作ではない処理の実行者
+var System = &User{Name: "system", Role: RoleAdmin}
 
 type User struct {
 	ID           int64     `json:"id"`
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"`
 	Name         string    `json:"name"`
+	Role         string    `json:"role"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
+
+// ValidRole は権限として使える値か
+func ValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleEditor, RoleAuthor:
+		return true
+	}
+	return false
+}
+
+// IsAdmin は管理者か
+func (u *User) IsAdmin() bool {
+	return u != nil && u.Role == RoleAdmin
+}
+
+// IsEditor は編集者以上（編集者か管理者）か
+func (u *User) IsEditor() bool {
+	return u != nil && (u.Role == RoleAdmin || u.Role == RoleEditor)
+}
+
+// RequireAdmin は管理者でなければ ErrForbidden を返す
+func RequireAdmin(u *User) error {
+	if !u.IsAdmin() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// RequireEditor は編集者以上でなければ ErrForbidden を返す
+func RequireEditor(u *User) error {
+	if !u.IsEditor() {
+		return ErrForbidden
+	}
+	return nil
+}
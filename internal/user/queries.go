@@ -0,0 +1,28 @@
+package user
+
+import "embed"
+
+//go:embed queries/*.sql
+var queryFS embed.FS
+
+func loadQuery(name string) string {
+	data, err := queryFS.ReadFile("queries/" + name)
+	if err != nil {
+		panic("failed to load query: " + name)
+	}
+	return string(data)
+}
+
+var (
+	queryGetAll         = loadQuery("get_all.sql")
+	queryGetByID        = loadQuery("get_by_id.sql")
+	queryGetByEmail     = loadQuery("get_by_email.sql")
+	queryCreate         = loadQuery("create.sql")
+	queryUpdate         = loadQuery("update.sql")
+	queryUpdatePassword = loadQuery("update_password.sql")
+	queryDelete         = loadQuery("delete.sql")
+	queryCountArticles  = loadQuery("count_articles.sql")
+	queryCountAdmins    = loadQuery("count_admins.sql")
+	queryDeleteSessions = loadQuery("delete_sessions.sql")
+	queryDeleteTokens   = loadQuery("delete_tokens.sql")
+)
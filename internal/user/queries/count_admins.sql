@@ -0,0 +1 @@
+SELECT COUNT(*) FROM users WHERE role = 'admin'
@@ -0,0 +1,2 @@
+-- ゴミ箱の記事も作成者として参照しているため含める
+SELECT COUNT(*) FROM articles WHERE author_id = ?
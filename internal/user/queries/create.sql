@@ -0,0 +1,2 @@
+INSERT INTO users (email, password_hash, name, role, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?)
@@ -0,0 +1 @@
+DELETE FROM users WHERE id = ?
@@ -0,0 +1,2 @@
+-- パスワードを変更したらログイン中のセッションを無効にする
+DELETE FROM sessions WHERE user_id = ?
@@ -0,0 +1,3 @@
+SELECT id, email, password_hash, name, role, created_at, updated_at
+FROM users
+ORDER BY id
@@ -0,0 +1,3 @@
+UPDATE users
+SET email = ?, name = ?, role = ?, updated_at = ?
+WHERE id = ?
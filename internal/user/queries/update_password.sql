@@ -0,0 +1,3 @@
+UPDATE users
+SET password_hash = ?, updated_at = ?
+WHERE id = ?
@@ -0,0 +1,133 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) GetAll() ([]User, error) {
+	rows, err := r.db.Query(queryGetAll)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *u)
+	}
+	return users, rows.Err()
+}
+
+func (r *Repository) GetByID(id int64) (*User, error) {
+	return scanUser(r.db.QueryRow(queryGetByID, id))
+}
+
+func (r *Repository) GetByEmail(email string) (*User, error) {
+	return scanUser(r.db.QueryRow(queryGetByEmail, email))
+}
+
+func scanUser(row interface{ Scan(...any) error }) (*User, error) {
+	var u User
+	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.Role, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Create はユーザーを作成する（メールアドレスが使用中なら ErrEmailTaken）
+func (r *Repository) Create(email, passwordHash, name, role string) (*User, error) {
+	now := time.Now()
+	result, err := r.db.Exec(queryCreate, email, passwordHash, name, role, now, now)
+	if err != nil {
+		return nil, uniqueError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// Update はメールアドレス・名前・権限を更新する（見つからなければ sql.ErrNoRows）
+func (r *Repository) Update(id int64, email, name, role string) (*User, error) {
+	if err := execAffected(r.db.Exec(queryUpdate, email, name, role, time.Now(), id)); err != nil {
+		return nil, uniqueError(err)
+	}
+	return r.GetByID(id)
+}
+
+// UpdatePassword はパスワードを変更し、ログイン中のセッションを削除する
+func (r *Repository) UpdatePassword(id int64, passwordHash string) error {
+	if err := execAffected(r.db.Exec(queryUpdatePassword, passwordHash, time.Now(), id)); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(queryDeleteSessions, id)
+	return err
+}
+
+// Delete はユーザーを削除する（セッションと API トークンも削除）
+func (r *Repository) Delete(id int64) error {
+	if err := execAffected(r.db.Exec(queryDelete, id)); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec(queryDeleteSessions, id); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(queryDeleteTokens, id)
+	return err
+}
+
+// CountArticles はユーザーが作成者の記事の数を返す（ゴミ箱の記事も含む）
+func (r *Repository) CountArticles(id int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(queryCountArticles, id).Scan(&count)
+	return count, err
+}
+
+func (r *Repository) CountAdmins() (int, error) {
+	var count int
+	err := r.db.QueryRow(queryCountAdmins).Scan(&count)
+	return count, err
+}
+
+// uniqueError は email の UNIQUE 制約違反を ErrEmailTaken にする
+func uniqueError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+// execAffected は対象の行がなければ sql.ErrNoRows を返す
+func execAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
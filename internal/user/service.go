@@ -0,0 +1,203 @@
+package user
+
+import (
+	"database/sql"
+	"net/mail"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// パスワードの長さ（bcrypt は72バイトまでしか使わない）
+const (
+	minPasswordLength = 8
+	maxPasswordBytes  = 72
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{repo: NewRepository(db)}
+}
+
+// GetAll はユーザーの一覧を返す（メールアドレスと権限を含むため管理者のみ）
+func (s *Service) GetAll(actor *User) ([]User, error) {
+	if err := RequireAdmin(actor); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAll()
+}
+
+// GetByID はユーザーを返す（管理者のみ）
+func (s *Service) GetByID(actor *User, id int64) (*User, error) {
+	if err := RequireAdmin(actor); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Create はパスワードを bcrypt でハッシュ化してユーザーを作成する（管理者のみ）。
+// role が空なら author
+func (s *Service) Create(actor *User, email, name, password, role string) (*User, error) {
+	if err := RequireAdmin(actor); err != nil {
+		return nil, err
+	}
+	if role == "" {
+		role = RoleAuthor
+	}
+	if !ValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Create(email, hash, strings.TrimSpace(name), role)
+}
+
+// Update はメールアドレス・名前・権限を変更する（管理者のみ）。
+// role が空なら変更しない。最後の管理者の権限は外せない
+func (s *Service) Update(actor *User, id int64, email, name, role string) (*User, error) {
+	if err := RequireAdmin(actor); err != nil {
+		return nil, err
+	}
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		role = current.Role
+	}
+	if !ValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if current.Role == RoleAdmin && role != RoleAdmin {
+		if err := s.checkLastAdmin(); err != nil {
+			return nil, err
+		}
+	}
+	email, err = normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Update(id, email, strings.TrimSpace(name), role)
+}
+
+// SetPassword はパスワードを変更する（本人か管理者のみ）
+func (s *Service) SetPassword(actor *User, id int64, password string) error {
+	if actor == nil || (actor.ID != id && !actor.IsAdmin()) {
+		return ErrForbidden
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdatePassword(id, hash)
+}
+
+// SetPasswordByEmail はメールアドレスで指定したユーザーのパスワードを変更する（CLI 用）
+func (s *Service) SetPasswordByEmail(email, password string) (*User, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.repo.GetByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SetPassword(System, u.ID, password); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Authenticate はメールアドレスとパスワードを確認する（一致しなければ ErrInvalidCredentials）。
+// パスワードが未設定のユーザーはログインできない
+func (s *Service) Authenticate(email, password string) (*User, error) {
+	u, err := s.repo.GetByEmail(strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// ユーザーの有無で応答時間が変わらないようにハッシュの比較はする
+			bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return u, nil
+}
+
+// dummyHash は存在しないユーザーのログインで比較に使うハッシュ
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Delete はユーザーを削除する（管理者のみ）。作成者になっている記事があれば ErrHasArticles
+func (s *Service) Delete(actor *User, id int64) error {
+	if err := RequireAdmin(actor); err != nil {
+		return err
+	}
+	u, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if u.Role == RoleAdmin {
+		if err := s.checkLastAdmin(); err != nil {
+			return err
+		}
+	}
+	count, err := s.repo.CountArticles(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrHasArticles
+	}
+	return s.repo.Delete(id)
+}
+
+// checkLastAdmin は管理者が1人しかいなければ ErrLastAdmin を返す（誰も管理できなくなるのを防ぐ）
+func (s *Service) checkLastAdmin() error {
+	count, err := s.repo.CountAdmins()
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// normalizeEmail はメールアドレスを検証し、小文字にそろえる（大文字・小文字違いの重複を防ぐ）
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
+
+func hashPassword(password string) (string, error) {
+	if len([]rune(password)) < minPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	if len(password) > maxPasswordBytes {
+		return "", ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"errors"
 	"log"
+	"os"
 
 	"cms/db"
+	"cms/internal/user"
 )
 
 func main() {
@@ -16,13 +19,13 @@ func main() {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
-	// ユーザー作成（必要に応じて編集）
-	_, err := db.DB.Exec(`
-		INSERT OR IGNORE INTO users (email, password_hash, name)
-		VALUES (?, ?, ?)
-	`, "kazu@example.com", "", "Kazu")
-
-	if err != nil {
+	// ユーザー作成（必要に応じて編集）。パスワードは SEED_PASSWORD、未指定なら開発用の既定値
+	password := os.Getenv("SEED_PASSWORD")
+	if password == "" {
+		password = "password123"
+	}
+	_, err := user.NewService(db.DB).Create(user.System, "kazu@example.com", "Kazu", password, user.RoleAdmin)
+	if err != nil && !errors.Is(err, user.ErrEmailTaken) {
 		log.Fatal("Failed to seed user:", err)
 	}
 